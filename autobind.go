@@ -0,0 +1,152 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// usageTagName is struct tag name of flag usage.
+const usageTagName = "usage"
+
+// durationType is reflect type of time.Duration.
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// AutoBind option registers a flag in flag set and binds an env variable for every leaf field of T.
+// Keys are derived from the field path like DefaultsFromStruct does, flag names are keys with dashes
+// instead of the key delimiter and env names are derived the way automatic env does, with underscores
+// instead of the key delimiter unless EnvKeyReplacer is set. So DB.Host field is bound to "db.host" key,
+// --db-host flag and APP_DB_HOST env variable with "APP" env prefix.
+//
+// Flag usage is taken from `usage` tag. Fields of unsupported types are skipped, already defined flags are kept.
+// The flag set is bound like BindFlags does, so it must be parsed before viper is provided.
+func AutoBind[T any](flagSet *pflag.FlagSet) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.walkStruct("", reflect.TypeOf((*T)(nil)).Elem(), func(key string, field reflect.StructField, ft reflect.Type) {
+			var flag = strings.ReplaceAll(key, keyDelimiter, "-")
+			if flagSet.Lookup(flag) == nil && !defineFlag(flagSet, flag, field.Tag.Get(usageTagName), ft) {
+				return
+			}
+
+			bundle.structEnvKeys = append(bundle.structEnvKeys, key)
+		})
+
+		bundle.flagSets = append(bundle.flagSets, flagSet)
+	})
+}
+
+// walkStruct calls fn for every leaf field of typ with its key derived like DefaultsFromStruct does,
+// nested structs are walked recursively.
+func (b *Bundle) walkStruct(prefix string, typ reflect.Type, fn func(key string, field reflect.StructField, ft reflect.Type)) {
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		var field = typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		var name, squash, skip = b.fieldKey(field)
+		if skip {
+			continue
+		}
+
+		var key = prefix
+		if !squash {
+			key = joinKey(prefix, name)
+		}
+
+		var ft = field.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+
+		if ft.Kind() == reflect.Struct {
+			b.walkStruct(key, ft, fn)
+			continue
+		}
+
+		fn(key, field, ft)
+	}
+}
+
+// defineFlag defines flag of type in flag set and reports whether the type is supported.
+func defineFlag(flagSet *pflag.FlagSet, name string, usage string, typ reflect.Type) bool {
+	if typ == durationType {
+		flagSet.Duration(name, 0, usage)
+		return true
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		flagSet.Bool(name, false, usage)
+	case reflect.String:
+		flagSet.String(name, "", usage)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		flagSet.Int(name, 0, usage)
+	case reflect.Int64:
+		flagSet.Int64(name, 0, usage)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		flagSet.Uint(name, 0, usage)
+	case reflect.Uint64:
+		flagSet.Uint64(name, 0, usage)
+	case reflect.Float32, reflect.Float64:
+		flagSet.Float64(name, 0, usage)
+	case reflect.Slice:
+		switch typ.Elem().Kind() {
+		case reflect.String:
+			flagSet.StringSlice(name, nil, usage)
+		case reflect.Int:
+			flagSet.IntSlice(name, nil, usage)
+		default:
+			return false
+		}
+	default:
+		return false
+	}
+
+	return true
+}
+
+// BindStruct option binds an env variable for every leaf field of the prototype struct, so env values
+// override every field without manual registration, e.g. DB.Host field is bound to APP_DB_HOST env variable
+// with "APP" env prefix. Keys and env names are derived like AutoBind does.
+//
+// The viper v1.15 used has no experimental struct binding of viper v1.18, which requires viper_bind_struct
+// build tag, so the struct is reflected and keys are bound one by one.
+func BindStruct(prototype interface{}) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.walkStruct("", reflect.TypeOf(prototype), func(key string, _ reflect.StructField, _ reflect.Type) {
+			bundle.structEnvKeys = append(bundle.structEnvKeys, key)
+		})
+	})
+}
+
+// bindStructEnvs binds env variables of struct keys, it is called once env prefix is set.
+func (b *Bundle) bindStructEnvs() {
+	if len(b.structEnvKeys) > 0 && b.envBindings == nil {
+		b.envBindings = make(map[string]string, len(b.structEnvKeys))
+	}
+
+	for _, key := range b.structEnvKeys {
+		var name = b.envName(key)
+		if b.envKeyReplacer == nil {
+			name = strings.ReplaceAll(name, keyDelimiter, "_")
+		}
+
+		_ = b.viper.BindEnv(key, name)
+		b.envBindings[key] = name
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// autoBindConfig is config struct of auto bind tests.
+type autoBindConfig struct {
+	Name string `mapstructure:"name"`
+	DB   struct {
+		Host    string        `mapstructure:"host" usage:"database host"`
+		Port    int           `mapstructure:"port"`
+		Timeout time.Duration `mapstructure:"timeout"`
+		Hosts   []string      `mapstructure:"hosts"`
+	} `mapstructure:"db"`
+	Labels map[string]string `mapstructure:"labels"`
+}
+
+func TestAutoBind(t *testing.T) {
+	t.Setenv("APP_DB_HOST", "env")
+	t.Setenv("APP_DB_PORT", "3306")
+
+	var (
+		flagSet = pflag.NewFlagSet("test", pflag.ContinueOnError)
+		b       = NewBundle(DontUseConfigFile(), EnvPrefix("APP"), AutoBind[autoBindConfig](flagSet))
+	)
+
+	var host = flagSet.Lookup("db-host")
+	require.NotNil(t, host)
+	assert.Equal(t, "database host", host.Usage)
+	assert.Equal(t, "int", flagSet.Lookup("db-port").Value.Type())
+	assert.Equal(t, "duration", flagSet.Lookup("db-timeout").Value.Type())
+	assert.Equal(t, "stringSlice", flagSet.Lookup("db-hosts").Value.Type())
+	assert.NotNil(t, flagSet.Lookup("name"))
+	assert.Nil(t, flagSet.Lookup("labels"), "unsupported field must be skipped")
+
+	require.NoError(t, flagSet.Parse([]string{"--db-port=6432", "--db-timeout=5s"}))
+
+	var v, err = provide(t, b, t.TempDir())
+	require.NoError(t, err)
+	assert.Equal(t, "env", v.GetString("db.host"), "env must be bound")
+	assert.Equal(t, 6432, v.GetInt("db.port"), "flag must take precedence over env")
+	assert.Equal(t, 5*time.Second, v.GetDuration("db.timeout"))
+}
+
+func TestBundle_BindStruct(t *testing.T) {
+	type Config struct {
+		Server struct {
+			HTTP struct {
+				Port    int           `mapstructure:"port"`
+				Timeout time.Duration `mapstructure:"timeout"`
+			} `mapstructure:"http"`
+		} `mapstructure:"server"`
+		Name string `mapstructure:"-"`
+	}
+
+	t.Setenv("ENV_SERVER_HTTP_PORT", "9090")
+	t.Setenv("ENV_SERVER_HTTP_TIMEOUT", "5s")
+
+	var dir = configDir(t, map[string]string{
+		"config.json": `{"server": {"http": {"port": 8080}}}`,
+	})
+
+	var b = NewBundle(BindStruct(Config{}))
+
+	var v, err = provide(t, b, dir)
+	require.NoError(t, err)
+	assert.Contains(t, v.AllKeys(), "server.http.timeout", "key absent in config file must be bound")
+
+	var config Config
+	require.NoError(t, b.ReadInto("server", &config.Server))
+	assert.Equal(t, 9090, config.Server.HTTP.Port, "deep field must be overridden by env")
+	assert.Equal(t, 5*time.Second, config.Server.HTTP.Timeout)
+
+	b = NewBundle()
+
+	v, err = provide(t, b, dir)
+	require.NoError(t, err)
+	assert.NotContains(t, v.AllKeys(), "server.http.timeout", "automatic env must not bind unknown keys")
+}
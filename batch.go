@@ -0,0 +1,87 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cast"
+	"github.com/spf13/viper"
+)
+
+// SetBatch sets the values for the keys in the override register all-or-nothing under the bundle lock,
+// unless config is read-only. Values are validated first: a value must be convertible to the type of the
+// key default value, match allowed values of RequireEnum key and required keys can't be set to nil.
+// If any value is invalid, no value is set and the error lists all invalid values.
+//
+// View and bundle getters take the bundle read lock, so they don't see partially set values. The viper
+// instance resolved from the container isn't guarded by the lock, it must not be read concurrently.
+func (b *Bundle) SetBatch(values map[string]interface{}) error {
+	return b.mutate(func(v *viper.Viper) error {
+		var keys = make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		var messages []string
+		for _, key := range keys {
+			if err := b.checkBatchValue(strings.ToLower(key), values[key]); err != nil {
+				messages = append(messages, fmt.Sprintf("'%s' : %s", key, err))
+			}
+		}
+
+		if len(messages) > 0 {
+			return fmt.Errorf("invalid batch values : %s", strings.Join(messages, "; "))
+		}
+
+		for _, key := range keys {
+			v.Set(b.nsKey(key), values[key])
+		}
+
+		return nil
+	})
+}
+
+// checkBatchValue checks value of the namespace relative key against default value type, enum and required
+// constraints.
+func (b *Bundle) checkBatchValue(key string, value interface{}) error {
+	if value == nil {
+		for _, required := range b.required {
+			if strings.EqualFold(required, key) {
+				return errors.New("required key can't be unset")
+			}
+		}
+
+		return nil
+	}
+
+	if def, ok := b.defaults[b.nsKey(key)]; ok {
+		if err := checkType(def, value); err != nil {
+			return err
+		}
+	}
+
+	for _, e := range b.enums {
+		if !strings.EqualFold(e.key, key) {
+			continue
+		}
+
+		var s = cast.ToString(value)
+		for _, item := range e.allowed {
+			if s == item {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("invalid value %q, allowed values are [%s]", s, strings.Join(e.allowed, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_SetBatch(t *testing.T) {
+	var (
+		dir = configDir(t, map[string]string{"config.json": `{"db": {"host": "localhost"}, "mode": "dev"}`})
+		b   = NewBundle(Default("db.port", 5432), RequireEnum("mode", "dev", "prod"), Required("db.host"))
+	)
+
+	var _, err = provide(t, b, dir)
+	require.NoError(t, err)
+
+	require.NoError(t, b.SetBatch(map[string]interface{}{"db.port": "6432", "mode": "prod", "debug": true}))
+	assert.Equal(t, 6432, b.View().GetInt("db.port"))
+	assert.Equal(t, "prod", b.View().GetString("mode"))
+	assert.True(t, b.View().GetBool("debug"))
+
+	err = b.SetBatch(map[string]interface{}{
+		"db.port": "not a port",
+		"mode":    "stage",
+		"db.host": nil,
+		"name":    "batch",
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "'db.host' : required key can't be unset")
+	assert.Contains(t, err.Error(), "'db.port' : ")
+	assert.Contains(t, err.Error(), `'mode' : invalid value "stage", allowed values are [dev, prod]`)
+
+	assert.Equal(t, 6432, b.View().GetInt("db.port"), "rejected batch must not be applied")
+	assert.Equal(t, "prod", b.View().GetString("mode"))
+	assert.Equal(t, "localhost", b.View().GetString("db.host"))
+	assert.False(t, b.View().IsSet("name"), "valid value of rejected batch must not be applied")
+
+	b = NewBundle(ReadOnly())
+	_, err = provide(t, b, dir)
+	require.NoError(t, err)
+	assert.ErrorIs(t, b.SetBatch(map[string]interface{}{"mode": "prod"}), ErrReadOnly)
+}
+
+func TestBundle_SetBatchConcurrent(t *testing.T) {
+	var b = NewBundle()
+
+	var _, err = provide(t, b, configDir(t, map[string]string{"config.json": `{"a": 0, "b": 0}`}))
+	require.NoError(t, err)
+
+	var (
+		wg      sync.WaitGroup
+		done    = make(chan struct{})
+		partial int32
+	)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		var view = b.View()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				if settings := view.AllSettings(); settings["a"] != settings["b"] {
+					atomic.AddInt32(&partial, 1)
+				}
+			}
+		}
+	}()
+
+	for i := 1; i <= 100; i++ {
+		require.NoError(t, b.SetBatch(map[string]interface{}{"a": i, "b": i}))
+	}
+
+	close(done)
+	wg.Wait()
+
+	assert.Zero(t, atomic.LoadInt32(&partial), "readers must not see partially set batch")
+
+	assert.Equal(t, 100, b.View().GetInt("a"))
+	assert.Equal(t, 100, b.View().GetInt("b"))
+}
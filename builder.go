@@ -0,0 +1,84 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import "strings"
+
+// Builder is fluent alternative of bundle options, it produces the same bundle as NewBundle
+// with equivalent options.
+type Builder struct {
+	options []Option
+}
+
+// NewBuilder create builder instance.
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// WithAutomaticEnv adds AutomaticEnv option.
+func (b *Builder) WithAutomaticEnv() *Builder {
+	return b.With(AutomaticEnv())
+}
+
+// WithEnvPrefix adds EnvPrefix option.
+func (b *Builder) WithEnvPrefix(value string) *Builder {
+	return b.With(EnvPrefix(value))
+}
+
+// WithEnvKeyReplacer adds EnvKeyReplacer option.
+func (b *Builder) WithEnvKeyReplacer(value *strings.Replacer) *Builder {
+	return b.With(EnvKeyReplacer(value))
+}
+
+// WithConfigFile adds ConfigFile option.
+func (b *Builder) WithConfigFile(value string) *Builder {
+	return b.With(ConfigFile(value))
+}
+
+// WithConfigName adds ConfigName option.
+func (b *Builder) WithConfigName(value string) *Builder {
+	return b.With(ConfigName(value))
+}
+
+// WithConfigPath adds ConfigPath option.
+func (b *Builder) WithConfigPath(value string) *Builder {
+	return b.With(ConfigPath(value))
+}
+
+// WithConfigType adds ConfigType option.
+func (b *Builder) WithConfigType(value string) *Builder {
+	return b.With(ConfigType(value))
+}
+
+// WithoutConfigFile adds DontUseConfigFile option.
+func (b *Builder) WithoutConfigFile() *Builder {
+	return b.With(DontUseConfigFile())
+}
+
+// WithDefault adds Default option.
+func (b *Builder) WithDefault(key string, value interface{}) *Builder {
+	return b.With(Default(key, value))
+}
+
+// WithRequired adds Required option.
+func (b *Builder) WithRequired(keys ...string) *Builder {
+	return b.With(Required(keys...))
+}
+
+// WithSecret adds Secret option.
+func (b *Builder) WithSecret(keys ...string) *Builder {
+	return b.With(Secret(keys...))
+}
+
+// With adds any options.
+func (b *Builder) With(options ...Option) *Builder {
+	b.options = append(b.options, options...)
+	return b
+}
+
+// Build create bundle instance the same way as NewBundle.
+func (b *Builder) Build() *Bundle {
+	return NewBundle(b.options...)
+}
@@ -0,0 +1,78 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder(t *testing.T) {
+	t.Setenv("APP_DB_PORT", "6432")
+
+	var dir = configDir(t, map[string]string{
+		"settings.yaml": "db:\n  host: localhost\n  password: secret\n",
+	})
+
+	var (
+		built = NewBuilder().
+			WithAutomaticEnv().
+			WithEnvPrefix("APP").
+			WithEnvKeyReplacer(strings.NewReplacer(".", "_")).
+			WithConfigName("settings").
+			WithConfigType("yaml").
+			WithDefault("db.port", 5432).
+			WithDefault("db.name", "app").
+			WithRequired("db.host").
+			WithSecret("db.password").
+			Build()
+		optioned = NewBundle(
+			AutomaticEnv(),
+			EnvPrefix("APP"),
+			EnvKeyReplacer(strings.NewReplacer(".", "_")),
+			ConfigName("settings"),
+			ConfigType("yaml"),
+			Default("db.port", 5432),
+			Default("db.name", "app"),
+			Required("db.host"),
+			Secret("db.password"),
+		)
+	)
+
+	var dumps [2]bytes.Buffer
+	for i, b := range []*Bundle{built, optioned} {
+		var v, err = provide(t, b, dir)
+		require.NoError(t, err)
+		assert.Equal(t, 6432, v.GetInt("db.port"))
+		assert.Equal(t, "localhost", v.GetString("db.host"))
+		require.NoError(t, b.DebugDump(&dumps[i]))
+	}
+
+	assert.Equal(t, dumps[1].String(), dumps[0].String())
+	assert.Contains(t, dumps[0].String(), secretMask)
+}
+
+func TestBuilder_ConfigFile(t *testing.T) {
+	var (
+		dir  = configDir(t, map[string]string{"custom/app.json": `{"name": "app"}`})
+		file = filepath.Join(dir, "custom", "app.json")
+	)
+
+	var v, err = provide(t, NewBuilder().WithConfigFile(file).Build(), t.TempDir())
+	require.NoError(t, err)
+	assert.Equal(t, "app", v.GetString("name"))
+
+	v, err = provide(t, NewBuilder().WithConfigName("app").WithConfigPath(filepath.Dir(file)).Build(), t.TempDir())
+	require.NoError(t, err)
+	assert.Equal(t, file, v.ConfigFileUsed())
+
+	_, err = provide(t, NewBuilder().WithoutConfigFile().WithRequired("name").Build(), dir)
+	assert.ErrorIs(t, err, ErrUndefinedKey)
+}
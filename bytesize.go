@@ -0,0 +1,118 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/cast"
+)
+
+// ByteSize is byte quantity decoded from human-readable byte size, e.g. "10MB" or "1GiB", by StringToBytesHookFunc.
+type ByteSize int64
+
+var (
+	// byteSizePattern is pattern of human-readable byte size.
+	byteSizePattern = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?)\s*([a-zA-Z]*)$`)
+
+	// byteSizeUnits are multipliers of byte size units, decimal units are powers of 1000
+	// and binary units are powers of 1024.
+	byteSizeUnits = map[string]float64{
+		"":    1,
+		"b":   1,
+		"k":   1e3,
+		"kb":  1e3,
+		"m":   1e6,
+		"mb":  1e6,
+		"g":   1e9,
+		"gb":  1e9,
+		"t":   1e12,
+		"tb":  1e12,
+		"ki":  1 << 10,
+		"kib": 1 << 10,
+		"mi":  1 << 20,
+		"mib": 1 << 20,
+		"gi":  1 << 30,
+		"gib": 1 << 30,
+		"ti":  1 << 40,
+		"tib": 1 << 40,
+	}
+)
+
+// GetBytes returns value of key parsed as human-readable byte size, for example "512", "10MB" or "1GiB".
+// Decimal units (KB, MB, GB, TB) are powers of 1000 and binary units (KiB, MiB, GiB, TiB) are powers of 1024.
+func (b *Bundle) GetBytes(key string) (int64, error) {
+	key = b.nsKey(key)
+	defer b.rlock(key)()
+
+	if !b.viper.IsSet(key) {
+		return 0, fmt.Errorf("unable to get bytes of '%s' : %w", key, ErrUndefinedKey)
+	}
+
+	var value, err = cast.ToStringE(b.viper.Get(key))
+	if err != nil {
+		return 0, fmt.Errorf("unable to get bytes of '%s' : %w", key, err)
+	}
+
+	var size int64
+	if size, err = ParseBytes(value); err != nil {
+		return 0, fmt.Errorf("unable to get bytes of '%s' : %w", key, err)
+	}
+
+	return size, nil
+}
+
+// ParseBytes parses human-readable byte size, see GetBytes for supported units.
+func ParseBytes(value string) (int64, error) {
+	var matches = byteSizePattern.FindStringSubmatch(strings.TrimSpace(value))
+	if matches == nil {
+		return 0, fmt.Errorf("invalid byte size %q", value)
+	}
+
+	var multiplier, ok = byteSizeUnits[strings.ToLower(matches[2])]
+	if !ok {
+		return 0, fmt.Errorf("invalid byte size unit %q", matches[2])
+	}
+
+	var number, err = strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid byte size %q : %w", value, err)
+	}
+
+	var size = number * multiplier
+	if size > math.MaxInt64 {
+		return 0, fmt.Errorf("byte size %q overflows int64", value)
+	}
+
+	return int64(size), nil
+}
+
+// StringToBytesHookFunc returns decode hook converting human-readable byte size strings to ByteSize, other
+// target types aren't affected. Plain numerals without unit are passed through to the regular number decoding.
+func StringToBytesHookFunc() mapstructure.DecodeHookFuncType {
+	return func(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+		if f.Kind() != reflect.String || t != reflect.TypeOf(ByteSize(0)) {
+			return data, nil
+		}
+
+		var value = strings.TrimSpace(data.(string))
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			return data, nil
+		}
+
+		var size, err = ParseBytes(value)
+		if err != nil {
+			return nil, err
+		}
+
+		return ByteSize(size), nil
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBytes(t *testing.T) {
+	var tests = []struct {
+		value string
+		size  int64
+	}{
+		{value: "512", size: 512},
+		{value: "512B", size: 512},
+		{value: "10KB", size: 10_000},
+		{value: "10 kb", size: 10_000},
+		{value: "1.5MB", size: 1_500_000},
+		{value: "2G", size: 2_000_000_000},
+		{value: "1TB", size: 1_000_000_000_000},
+		{value: "1KiB", size: 1024},
+		{value: "10MiB", size: 10 << 20},
+		{value: "1GiB", size: 1 << 30},
+		{value: " 1Ti ", size: 1 << 40},
+	}
+
+	for _, test := range tests {
+		var size, err = ParseBytes(test.value)
+		require.NoError(t, err, test.value)
+		assert.Equal(t, test.size, size, test.value)
+	}
+
+	for _, value := range []string{"", "abc", "-1KB", "10XB", "1.2.3MB", "100000000TB"} {
+		var _, err = ParseBytes(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestBundle_GetBytes(t *testing.T) {
+	var dir = configDir(t, map[string]string{
+		"config.json": `{"cache": {"size": "64MiB", "limit": 1024, "bad": "much"}}`,
+	})
+
+	var b = NewBundle()
+
+	var _, err = provide(t, b, dir)
+	require.NoError(t, err)
+
+	var size int64
+	size, err = b.GetBytes("cache.size")
+	require.NoError(t, err)
+	assert.Equal(t, int64(64<<20), size)
+
+	size, err = b.GetBytes("cache.limit")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1024), size)
+
+	_, err = b.GetBytes("cache.bad")
+	assert.Error(t, err)
+
+	_, err = b.GetBytes("cache.missing")
+	assert.ErrorIs(t, err, ErrUndefinedKey)
+}
+
+func TestStringToBytesHookFunc(t *testing.T) {
+	type Config struct {
+		Size   ByteSize
+		Plain  ByteSize
+		Offset int64
+		Name   string
+	}
+
+	var out Config
+	require.NoError(t, NewBundle().decode(map[string]interface{}{
+		"size":   "10MB",
+		"plain":  "512",
+		"offset": "-5",
+		"name":   "1KB",
+	}, &out))
+
+	assert.Equal(t, Config{Size: 10_000_000, Plain: 512, Offset: -5, Name: "1KB"}, out)
+	assert.Error(t, NewBundle().decode(map[string]interface{}{"size": "big"}, &out))
+}
@@ -0,0 +1,60 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// captureFilePerm is permissions of captured config snapshot, it may contain secret values.
+const captureFilePerm = 0o600
+
+// CaptureTo option writes all resolved settings (config file, env, flags and defaults) to the snapshot
+// file after config is loaded, the file format is inferred from its extension. Secret values are not
+// redacted, so the snapshot can be replayed verbatim.
+func CaptureTo(path string) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.captureTo = path
+	})
+}
+
+// ReplayFrom option loads config from the snapshot file written by CaptureTo as the sole source,
+// config files, automatic env and flags are bypassed.
+func ReplayFrom(path string) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.replayFrom = path
+	})
+}
+
+// capture writes all settings to the snapshot file.
+func (b *Bundle) capture() (err error) {
+	var buf bytes.Buffer
+	if err = encode(&buf, b.fileType(b.captureTo), b.viper.AllSettings(), ExportOptions{}); err != nil {
+		return fmt.Errorf("unable to capture config : %w", err)
+	}
+
+	if err = writeFileAtomic(b.captureTo, buf.Bytes(), captureFilePerm); err != nil {
+		return fmt.Errorf("unable to capture config : '%s' : %w", b.captureTo, err)
+	}
+
+	return nil
+}
+
+// replay loads config from the snapshot file.
+func (b *Bundle) replay() (err error) {
+	var settings map[string]interface{}
+	if settings, err = b.readFile(b.replayFrom); err != nil {
+		return fmt.Errorf("unable to replay config : '%s' : %w", b.replayFrom, err)
+	}
+
+	if err = b.replaceConfig(settings); err != nil {
+		return fmt.Errorf("unable to replay config : '%s' : %w", b.replayFrom, err)
+	}
+
+	b.loaded = true
+
+	return nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_CaptureReplay(t *testing.T) {
+	t.Setenv("ENV_DB_PORT", "6432")
+
+	var flagSet = pflag.NewFlagSet("app", pflag.ContinueOnError)
+	flagSet.String("log-level", "", "log level")
+	require.NoError(t, flagSet.Parse([]string{"--log-level=debug"}))
+
+	var (
+		dir = configDir(t, map[string]string{
+			"config.json": `{"db": {"host": "localhost", "port": 5432}, "log": {"level": "info"}}`,
+		})
+		snapshot = filepath.Join(t.TempDir(), "snapshot.yaml")
+		captured = NewBundle(CaptureTo(snapshot), BindFlags(flagSet), Default("name", "app"))
+	)
+
+	var v, err = provide(t, captured, dir)
+	require.NoError(t, err)
+
+	var settings = v.AllSettings()
+
+	var info os.FileInfo
+	info, err = os.Stat(snapshot)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(captureFilePerm), info.Mode().Perm())
+
+	t.Setenv("ENV_DB_PORT", "7432")
+
+	var replayed = NewBundle(ReplayFrom(snapshot))
+
+	v, err = provide(t, replayed, t.TempDir())
+	require.NoError(t, err)
+	assert.Equal(t, settings, v.AllSettings())
+	assert.Equal(t, "localhost", v.GetString("db.host"))
+	assert.Equal(t, 6432, v.GetInt("db.port"), "env must be bypassed on replay")
+	assert.Equal(t, "debug", v.GetString("log.level"))
+	assert.Equal(t, "app", v.GetString("name"))
+	assert.NoError(t, replayed.Ready())
+}
+
+func TestBundle_ReplayMissing(t *testing.T) {
+	var _, err = provide(t, NewBundle(ReplayFrom(filepath.Join(t.TempDir(), "missing.json"))), t.TempDir())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unable to replay config")
+}
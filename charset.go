@@ -0,0 +1,50 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/encoding/ianaindex"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// Charset option decodes config files from the charset to UTF-8 before parsing, e.g. "UTF-16LE" or
+// "windows-1251". Charset names are IANA names, a byte order mark is respected and stripped.
+// UTF-8 files are passed through unchanged.
+func Charset(enc string) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.charset = enc
+	})
+}
+
+// isCharsetDecoded reports whether config files are decoded from the non UTF-8 charset.
+func (b *Bundle) isCharsetDecoded() bool {
+	return len(b.charset) > 0 && !strings.EqualFold(b.charset, "utf-8")
+}
+
+// decodeCharset decodes data from the bundle charset to UTF-8.
+func (b *Bundle) decodeCharset(data []byte) ([]byte, error) {
+	if !b.isCharsetDecoded() {
+		return data, nil
+	}
+
+	var enc, err = ianaindex.IANA.Encoding(b.charset)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode config charset : %w", err)
+	}
+
+	if enc == nil {
+		return nil, fmt.Errorf("unable to decode config charset : unsupported charset '%s'", b.charset)
+	}
+
+	if data, _, err = transform.Bytes(unicode.BOMOverride(enc.NewDecoder()), data); err != nil {
+		return nil, fmt.Errorf("unable to decode config charset '%s' : %w", b.charset, err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,49 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+func TestBundle_Charset(t *testing.T) {
+	const content = `{"greeting": "привет"}`
+
+	var tests = []struct {
+		name    string
+		charset string
+		enc     encoding.Encoding
+	}{
+		{name: "utf-16le", charset: "UTF-16LE", enc: unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM)},
+		{name: "utf-16le bom", charset: "UTF-16LE", enc: unicode.UTF16(unicode.LittleEndian, unicode.UseBOM)},
+		{name: "windows-1251", charset: "windows-1251", enc: charmap.Windows1251},
+		{name: "utf-8", charset: "utf-8", enc: unicode.UTF8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var data, err = tt.enc.NewEncoder().String(content)
+			require.NoError(t, err)
+
+			var dir = configDir(t, map[string]string{"config.json": data})
+
+			v, err := provide(t, NewBundle(Charset(tt.charset)), dir)
+			require.NoError(t, err)
+			require.Equal(t, "привет", v.GetString("greeting"))
+		})
+	}
+}
+
+func TestBundle_CharsetUnknown(t *testing.T) {
+	var dir = configDir(t, map[string]string{"config.json": `{}`})
+
+	var _, err = provide(t, NewBundle(Charset("unknown-charset")), dir)
+	require.ErrorContains(t, err, "unable to decode config charset")
+}
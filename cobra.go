@@ -0,0 +1,12 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import "github.com/spf13/cobra"
+
+// BindCobra option binds changed flags of cobra command to viper keys.
+func BindCobra(cmd *cobra.Command) Option {
+	return BindFlags(cmd.Flags())
+}
@@ -0,0 +1,29 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindCobra(t *testing.T) {
+	var cmd = &cobra.Command{Use: "app"}
+	cmd.Flags().String("log-level", "info", "log level")
+	cmd.Flags().Int("max-conns", 10, "max connections")
+	require.NoError(t, cmd.Flags().Parse([]string{"--log-level", "debug"}))
+
+	var dir = configDir(t, map[string]string{
+		"config.json": `{"log":{"level":"warn"},"max":{"conns":5}}`,
+	})
+
+	var v, err = provide(t, NewBundle(BindCobra(cmd)), dir)
+	require.NoError(t, err)
+	assert.Equal(t, "debug", v.GetString("log.level"))
+	assert.Equal(t, 5, v.GetInt("max.conns"), "unchanged flag must not be bound")
+}
@@ -0,0 +1,94 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Compatible checks the other config file has the same shape as the current config file: the same keys
+// with values of the same types, e.g. before deploying the new config file. Numbers of any type are
+// compatible, so int and float values of json and yaml files don't differ. The error lists added, removed
+// and type changed keys of the other file and wraps ErrIncompatibleConfig.
+func (b *Bundle) Compatible(otherPath string) error {
+	defer b.rlock("")()
+
+	if !b.configFileFound {
+		return fmt.Errorf("unable to check config compatibility : %w", ErrNoConfigFile)
+	}
+
+	var other, err = b.readFile(otherPath)
+	if err != nil {
+		return fmt.Errorf("unable to read config file '%s' : %w", otherPath, err)
+	}
+
+	var (
+		current  = flatten(lowercaseKeys(b.fileConfig))
+		next     = flatten(lowercaseKeys(other))
+		messages []string
+		added    []string
+		removed  []string
+		changed  []string
+	)
+
+	for key, value := range next {
+		var old, ok = current[key]
+		switch {
+		case !ok:
+			added = append(added, key)
+		case shapeType(old) != shapeType(value):
+			changed = append(changed, fmt.Sprintf("%s %s to %s", key, shapeType(old), shapeType(value)))
+		}
+	}
+
+	for key := range current {
+		if _, ok := next[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+
+	for _, group := range []struct {
+		name string
+		keys []string
+	}{{"added", added}, {"removed", removed}, {"type changed", changed}} {
+		if len(group.keys) > 0 {
+			sort.Strings(group.keys)
+			messages = append(messages, fmt.Sprintf("%s keys [%s]", group.name, strings.Join(group.keys, ", ")))
+		}
+	}
+
+	if len(messages) > 0 {
+		return fmt.Errorf("config file '%s' is incompatible, %s : %w", otherPath, strings.Join(messages, ", "), ErrIncompatibleConfig)
+	}
+
+	return nil
+}
+
+// shapeType returns type name of config value shape.
+func shapeType(value interface{}) string {
+	if value == nil {
+		return "null"
+	}
+
+	switch reflect.TypeOf(value).Kind() {
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		return "list"
+	case reflect.Map:
+		return "map"
+	default:
+		return reflect.TypeOf(value).String()
+	}
+}
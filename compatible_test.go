@@ -0,0 +1,44 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_Compatible(t *testing.T) {
+	var (
+		dir = configDir(t, map[string]string{
+			"config.json":       `{"db": {"host": "localhost", "port": 5432, "hosts": ["a"]}, "debug": false}`,
+			"same.yaml":         "db:\n  host: remote\n  port: 6432.5\n  hosts: [b, c]\ndebug: true\n",
+			"incompatible.json": `{"db": {"host": "localhost", "port": "5432", "hosts": ["a"]}, "cache": {"ttl": "1m"}}`,
+		})
+		b = NewBundle()
+	)
+
+	var _, err = provide(t, b, dir)
+	require.NoError(t, err)
+
+	require.NoError(t, b.Compatible(filepath.Join(dir, "same.yaml")), "values and number types may differ")
+
+	var other = filepath.Join(dir, "incompatible.json")
+
+	err = b.Compatible(other)
+	require.ErrorIs(t, err, ErrIncompatibleConfig)
+	assert.EqualError(t, err, "config file '"+other+"' is incompatible, added keys [cache.ttl], removed keys [debug], "+
+		"type changed keys [db.port number to string] : "+ErrIncompatibleConfig.Error())
+
+	err = b.Compatible(filepath.Join(dir, "missing.json"))
+	assert.ErrorContains(t, err, "unable to read config file")
+
+	b = NewBundle(DontUseConfigFile())
+	_, err = provide(t, b, dir)
+	require.NoError(t, err)
+	assert.ErrorIs(t, b.Compatible(other), ErrNoConfigFile)
+}
@@ -0,0 +1,82 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// configValueFlag is name of typed config value override flag.
+const configValueFlag = "config-value"
+
+// ConfigValueFlag option registers repeatable --config-value flag overriding config values with explicit
+// type, e.g. --config-value db.port:int=5432. Supported types are int, bool, float, duration and string.
+func ConfigValueFlag() Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.configValueFlag = true
+	})
+}
+
+// applyConfigValues sets values of --config-value flags as overrides.
+func (b *Bundle) applyConfigValues(flagSet *pflag.FlagSet) error {
+	var name = b.flagName(configValueFlag)
+
+	var items, err = flagSet.GetStringArray(name)
+	if err != nil {
+		return fmt.Errorf("unable to get %s flag value : %w", name, err)
+	}
+
+	for _, item := range items {
+		var key, value, err = parseConfigValue(item)
+		if err != nil {
+			return fmt.Errorf("invalid %s flag value %q : %w", name, item, err)
+		}
+
+		b.viper.Set(key, value)
+	}
+
+	return nil
+}
+
+// parseConfigValue parses key:type=value triple and returns the value coerced to the type.
+func parseConfigValue(item string) (key string, value interface{}, err error) {
+	var spec, raw, ok = strings.Cut(item, "=")
+
+	var typ string
+	if ok {
+		key, typ, ok = strings.Cut(spec, ":")
+	}
+
+	if !ok || len(key) == 0 {
+		return "", nil, errors.New("expected key:type=value format")
+	}
+
+	switch typ {
+	case "int":
+		value, err = strconv.Atoi(raw)
+	case "bool":
+		value, err = strconv.ParseBool(raw)
+	case "float":
+		value, err = strconv.ParseFloat(raw, 64)
+	case "duration":
+		value, err = time.ParseDuration(raw)
+	case "string":
+		value = raw
+	default:
+		return "", nil, fmt.Errorf("unknown type '%s'", typ)
+	}
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	return key, value, nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_ConfigValueFlag(t *testing.T) {
+	var dir = configDir(t, map[string]string{
+		"config.json": `{"db": {"port": 3306}}`,
+	})
+
+	var v, err = provide(t, NewBundle(ConfigValueFlag()), dir,
+		"--config-value", "db.port:int=5432",
+		"--config-value", "db.debug:bool=true",
+		"--config-value", "db.ratio:float=0.5",
+		"--config-value", "db.timeout:duration=1m30s",
+		"--config-value", "db.name:string=app:main=1",
+	)
+
+	require.NoError(t, err)
+	require.Equal(t, 5432, v.Get("db.port"))
+	require.Equal(t, true, v.Get("db.debug"))
+	require.Equal(t, 0.5, v.Get("db.ratio"))
+	require.Equal(t, 90*time.Second, v.Get("db.timeout"))
+	require.Equal(t, "app:main=1", v.Get("db.name"))
+}
+
+func TestBundle_ConfigValueFlagInvalid(t *testing.T) {
+	var dir = configDir(t, map[string]string{"config.json": `{}`})
+
+	var tests = []struct {
+		name  string
+		value string
+		err   string
+	}{
+		{name: "unknown type", value: "db.port:uint=1", err: "unknown type 'uint'"},
+		{name: "missing type", value: "db.port=1", err: "expected key:type=value format"},
+		{name: "missing value", value: "db.port:int", err: "expected key:type=value format"},
+		{name: "missing key", value: ":int=1", err: "expected key:type=value format"},
+		{name: "malformed value", value: "db.port:int=abc", err: "invalid syntax"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var _, err = provide(t, NewBundle(ConfigValueFlag()), dir, "--config-value", tt.value)
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestParseConfigValue(t *testing.T) {
+	var tests = []struct {
+		item  string
+		key   string
+		value interface{}
+	}{
+		{item: "a:int=-1", key: "a", value: -1},
+		{item: "a:bool=false", key: "a", value: false},
+		{item: "a:float=1e3", key: "a", value: 1000.0},
+		{item: "a:duration=250ms", key: "a", value: 250 * time.Millisecond},
+		{item: "a.b:string=", key: "a.b", value: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.item, func(t *testing.T) {
+			var key, value, err = parseConfigValue(tt.item)
+			require.NoError(t, err)
+			require.Equal(t, tt.key, key)
+			require.Equal(t, tt.value, value)
+		})
+	}
+}
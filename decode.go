@@ -0,0 +1,192 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// Get returns value of key coerced to T, the def is returned when key is unset or value is uncoercible.
+func Get[T any](b *Bundle, key string, def T) T {
+	key = b.nsKey(key)
+	defer b.rlock(key)()
+
+	if !b.viper.IsSet(key) {
+		return def
+	}
+
+	var value T
+	if err := b.decode(b.viper.Get(key), &value); err != nil {
+		return def
+	}
+
+	return value
+}
+
+// ReadInto unmarshals subtree of key into out using bundle decode hooks and tag name.
+//
+// Input is weakly typed like viper does unless DisableWeaklyTypedInput option is used.
+func (b *Bundle) ReadInto(key string, out interface{}, opts ...viper.DecoderConfigOption) error {
+	key = b.nsKey(key)
+	defer b.rlock(key)()
+
+	if !b.viper.IsSet(key) {
+		return fmt.Errorf("unable to read '%s' : %w", key, ErrUndefinedKey)
+	}
+
+	// viper.Viper.UnmarshalKey doesn't merge nested defaults under config subtree, so the subtree
+	// is looked up in all settings.
+	var value, ok = lookup(b.viper.AllSettings(), key)
+	if !ok {
+		value = b.viper.Get(key)
+	}
+
+	var config = b.decoderConfig(out)
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	var decoder, err = mapstructure.NewDecoder(config)
+	if err == nil {
+		err = decoder.Decode(value)
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to read '%s' : %w", key, err)
+	}
+
+	return nil
+}
+
+// decode decodes input to output the same way as viper does on unmarshal.
+func (b *Bundle) decode(input interface{}, output interface{}) error {
+	var decoder, err = mapstructure.NewDecoder(b.decoderConfig(output))
+	if err != nil {
+		return err
+	}
+
+	return decoder.Decode(input)
+}
+
+// decoderConfig returns mapstructure decoder config with viper defaults and bundle decoder options.
+func (b *Bundle) decoderConfig(output interface{}) *mapstructure.DecoderConfig {
+	var config = &mapstructure.DecoderConfig{
+		Metadata:         nil,
+		Result:           output,
+		WeaklyTypedInput: true,
+	}
+
+	for _, opt := range b.decoderOptions() {
+		opt(config)
+	}
+
+	return config
+}
+
+// decoderOptions returns viper decoder options with bundle decode hooks, tag name and weak typing,
+// the duration hook is included unless disabled.
+func (b *Bundle) decoderOptions() []viper.DecoderConfigOption {
+	var hooks []mapstructure.DecodeHookFunc
+	if !b.noDurationHook {
+		hooks = append(hooks, mapstructure.StringToTimeDurationHookFunc())
+	}
+
+	hooks = append(hooks, mapstructure.StringToSliceHookFunc(","), StringToBytesHookFunc())
+	hooks = append(hooks, b.decodeHooks...)
+
+	var opts = []viper.DecoderConfigOption{
+		viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(hooks...)),
+		func(config *mapstructure.DecoderConfig) {
+			config.WeaklyTypedInput = !b.noWeakInput
+		},
+	}
+
+	if len(b.tagName) > 0 {
+		opts = append(opts, func(config *mapstructure.DecoderConfig) {
+			config.TagName = b.tagName
+		})
+	}
+
+	return opts
+}
+
+// Load builds bundle with options, reads config from the current working directory without DI container
+// and unmarshals all settings into T using bundle decode hooks and tag name. The returned closer stops
+// config watching, e.g. enabled by OnChange option, it must be called once config isn't used.
+func Load[T any](options ...Option) (_ *T, _ *viper.Viper, _ func() error, err error) {
+	var path string
+	if path, err = os.Getwd(); err != nil {
+		return nil, nil, nil, fmt.Errorf("unable to get working directory : %w", err)
+	}
+
+	var (
+		b       = NewBundle(options...)
+		flagSet *pflag.FlagSet
+	)
+
+	if flagSet, err = b.provideFlagSet(); err != nil {
+		return nil, nil, nil, fmt.Errorf("unable to parse flags : %w", err)
+	}
+
+	var (
+		ctx    = context.WithValue(context.Background(), "app.path", path)
+		v      *viper.Viper
+		closer func() error
+	)
+
+	if v, closer, err = b.provideViper(ctx, flagSet, nil, nil); err != nil {
+		return nil, nil, nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = closer()
+		}
+	}()
+
+	if err = b.Load(); err != nil {
+		return nil, nil, nil, err
+	}
+
+	var out = new(T)
+	if err = b.decode(b.nsSettings(v.AllSettings()), out); err != nil {
+		return nil, nil, nil, fmt.Errorf("unable to unmarshal config : %w", err)
+	}
+
+	return out, v, closer, nil
+}
+
+// GetSlice decodes elements of the key value into T using bundle decode hooks and tag name, e.g. list of
+// server definitions into []Server. An empty slice is returned, if the key is absent.
+func GetSlice[T any](b *Bundle, key string) ([]T, error) {
+	key = b.nsKey(key)
+	defer b.rlock(key)()
+
+	var value = b.viper.Get(key)
+	if value == nil {
+		return []T{}, nil
+	}
+
+	var items = reflect.ValueOf(value)
+	if items.Kind() != reflect.Slice && items.Kind() != reflect.Array {
+		return nil, fmt.Errorf("unable to decode key '%s' : expected slice, got %T", key, value)
+	}
+
+	var out = make([]T, items.Len())
+	for i := range out {
+		if err := b.decode(items.Index(i).Interface(), &out[i]); err != nil {
+			return nil, fmt.Errorf("unable to decode key '%s' element %d : %w", key, i, err)
+		}
+	}
+
+	return out, nil
+}
@@ -0,0 +1,202 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGet(t *testing.T) {
+	var dir = configDir(t, map[string]string{
+		"config.json": `{
+			"name": "app",
+			"port": "8080",
+			"ratio": 0.5,
+			"debug": "true",
+			"timeout": "5s",
+			"hosts": ["a", "b"],
+			"tags": "x,y",
+			"bad": {"nested": true}
+		}`,
+	})
+
+	var b = NewBundle()
+
+	var _, err = provide(t, b, dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, "app", Get(b, "name", "def"))
+	assert.Equal(t, 8080, Get(b, "port", 0))
+	assert.Equal(t, int64(8080), Get(b, "port", int64(0)))
+	assert.Equal(t, 0.5, Get(b, "ratio", 0.0))
+	assert.Equal(t, true, Get(b, "debug", false))
+	assert.Equal(t, 5*time.Second, Get(b, "timeout", time.Duration(0)))
+	assert.Equal(t, []string{"a", "b"}, Get(b, "hosts", []string(nil)))
+	assert.Equal(t, []string{"x", "y"}, Get(b, "tags", []string(nil)))
+
+	assert.Equal(t, "def", Get(b, "missing", "def"))
+	assert.Equal(t, 42, Get(b, "missing", 42))
+	assert.Equal(t, 7, Get(b, "bad", 7), "uncoercible value must return default")
+	assert.Equal(t, 9, Get(b, "name", 9), "uncoercible value must return default")
+}
+
+func TestBundle_ReadInto(t *testing.T) {
+	type DBConfig struct {
+		Host    string        `cfg:"host"`
+		Port    int           `cfg:"port"`
+		Timeout time.Duration `cfg:"timeout"`
+		Options []string      `cfg:"options"`
+	}
+
+	var dir = configDir(t, map[string]string{
+		"config.json": `{"db": {"host": "localhost", "port": "5432", "options": "a,b"}}`,
+	})
+
+	var b = NewBundle(TagName("cfg"), Default("db.timeout", "3s"))
+
+	var _, err = provide(t, b, dir)
+	require.NoError(t, err)
+
+	var db DBConfig
+	require.NoError(t, b.ReadInto("db", &db))
+	assert.Equal(t, DBConfig{
+		Host:    "localhost",
+		Port:    5432,
+		Timeout: 3 * time.Second,
+		Options: []string{"a", "b"},
+	}, db)
+
+	err = b.ReadInto("cache", &db)
+	require.ErrorIs(t, err, ErrUndefinedKey)
+	assert.Contains(t, err.Error(), "'cache'")
+}
+
+func TestBundle_DurationHook(t *testing.T) {
+	type Config struct {
+		Timeout  time.Duration `mapstructure:"timeout"`
+		Interval time.Duration `mapstructure:"interval"`
+	}
+
+	var dir = configDir(t, map[string]string{
+		"config.json": `{"http": {"timeout": "1m30s"}}`,
+	})
+
+	var b = NewBundle(Default("http.interval", 5*time.Second))
+
+	var _, err = provide(t, b, dir)
+	require.NoError(t, err)
+
+	var out Config
+	require.NoError(t, b.ReadInto("http", &out))
+	assert.Equal(t, Config{Timeout: 90 * time.Second, Interval: 5 * time.Second}, out)
+
+	b = NewBundle(DisableDurationHook())
+	_, err = provide(t, b, dir)
+	require.NoError(t, err)
+	assert.Error(t, b.ReadInto("http", &out), "duration string must not be decoded without hook")
+}
+
+func TestBundle_WeaklyTypedInput(t *testing.T) {
+	type Config struct {
+		Retries int  `mapstructure:"retries"`
+		Debug   bool `mapstructure:"debug"`
+	}
+
+	t.Setenv("ENV_APP_RETRIES", "3")
+	t.Setenv("ENV_APP_DEBUG", "true")
+
+	var dir = configDir(t, map[string]string{
+		"config.json": `{"app": {"retries": 1, "debug": false}}`,
+	})
+
+	for _, b := range []*Bundle{NewBundle(), NewBundle(DisableWeaklyTypedInput(), WeaklyTypedInput())} {
+		var _, err = provide(t, b, dir)
+		require.NoError(t, err)
+
+		var out Config
+		require.NoError(t, b.ReadInto("app", &out))
+		assert.Equal(t, Config{Retries: 3, Debug: true}, out)
+	}
+
+	var b = NewBundle(DisableWeaklyTypedInput())
+
+	var _, err = provide(t, b, dir)
+	require.NoError(t, err)
+
+	var out Config
+	assert.Error(t, b.ReadInto("app", &out), "string env values must not be decoded without weak typing")
+}
+
+func TestLoad(t *testing.T) {
+	type Config struct {
+		Name string `mapstructure:"name"`
+		DB   struct {
+			Host    string        `mapstructure:"host"`
+			Port    int           `mapstructure:"port"`
+			Timeout time.Duration `mapstructure:"timeout"`
+		} `mapstructure:"db"`
+	}
+
+	setArgs(t)
+	chdir(t, configDir(t, map[string]string{
+		"config.json": `{"name": "app", "db": {"host": "localhost", "timeout": "5s"}}`,
+	}))
+
+	var config, v, closer, err = Load[Config](Default("db.port", 5432), OnChange(func(fsnotify.Event) {}))
+	require.NoError(t, err)
+	require.NotNil(t, v)
+	require.NoError(t, closer(), "watching must be stopped by closer")
+	assert.Equal(t, "app", config.Name)
+	assert.Equal(t, "localhost", config.DB.Host)
+	assert.Equal(t, 5432, config.DB.Port)
+	assert.Equal(t, 5*time.Second, config.DB.Timeout)
+	assert.Equal(t, "localhost", v.GetString("db.host"))
+
+	chdir(t, t.TempDir())
+
+	_, _, _, err = Load[Config]()
+	assert.Error(t, err, "missing config file must fail load")
+}
+
+func TestGetSlice(t *testing.T) {
+	type Server struct {
+		Host    string        `cfg:"host"`
+		Port    int           `cfg:"port"`
+		Timeout time.Duration `cfg:"timeout"`
+	}
+
+	var dir = configDir(t, map[string]string{
+		"config.json": `{
+			"servers": [{"host": "a", "port": "80", "timeout": "1s"}, {"host": "b", "port": 443}],
+			"name": "app",
+			"invalid": [{"port": "abc"}]
+		}`,
+	})
+
+	var b = NewBundle(TagName("cfg"))
+
+	var _, err = provide(t, b, dir)
+	require.NoError(t, err)
+
+	servers, err := GetSlice[Server](b, "servers")
+	require.NoError(t, err)
+	assert.Equal(t, []Server{{Host: "a", Port: 80, Timeout: time.Second}, {Host: "b", Port: 443}}, servers)
+
+	servers, err = GetSlice[Server](b, "missing")
+	require.NoError(t, err)
+	assert.NotNil(t, servers)
+	assert.Empty(t, servers)
+
+	_, err = GetSlice[Server](b, "name")
+	assert.ErrorContains(t, err, "unable to decode key 'name' : expected slice, got string")
+
+	_, err = GetSlice[Server](b, "invalid")
+	assert.ErrorContains(t, err, "unable to decode key 'invalid' element 0")
+}
@@ -0,0 +1,48 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import "github.com/gozix/di"
+
+// TagDefaults is tag marks Defaults definitions, they are collected and set as default values on viper provide.
+const TagDefaults = "viper.defaults"
+
+// Defaults is nested map of default values contributed by other bundles, see AsDefaults.
+type Defaults map[string]interface{}
+
+// AsDefaults is syntax sugar for the di container, it marks Defaults definition, so feature bundles declare
+// their own defaults without the root knowing them:
+//
+//	builder.Provide(func() viper.Defaults {
+//		return viper.Defaults{"cache": map[string]interface{}{"ttl": "1m"}}
+//	}, viper.AsDefaults())
+//
+// Defaults are merged in definition order, the later value of the same key wins. Default values set
+// by bundle options take precedence over them. Every bundle instance collects all of them.
+func AsDefaults() di.ProvideOption {
+	return di.Tags{{
+		Name: TagDefaults,
+	}}
+}
+
+// applyProvidedDefaults sets collected default values of keys, unless they are set by bundle options.
+func (b *Bundle) applyProvidedDefaults(defaults []Defaults) {
+	if len(defaults) == 0 {
+		return
+	}
+
+	var own = make(map[string]bool, len(b.defaults))
+	for key := range b.defaults {
+		own[key] = true
+	}
+
+	for _, values := range defaults {
+		for key, value := range flatten(lowercaseKeys(values)) {
+			if !own[key] {
+				b.setDefault(key, value)
+			}
+		}
+	}
+}
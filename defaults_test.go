@@ -0,0 +1,53 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gozix/di"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAsDefaults(t *testing.T) {
+	setArgs(t)
+
+	var dir = configDir(t, map[string]string{
+		"config.json": `{"cache": {"size": 100}}`,
+	})
+
+	var builder, err = di.NewBuilder(
+		di.Provide(func() context.Context {
+			return context.WithValue(context.Background(), "app.path", dir)
+		}),
+		di.Provide(func() Defaults {
+			return Defaults{"cache": map[string]interface{}{"ttl": "1m", "size": 10, "policy": "lru"}}
+		}, AsDefaults()),
+		di.Provide(func() Defaults {
+			return Defaults{"HTTP": map[string]interface{}{"port": 8080}, "cache": map[string]interface{}{"ttl": "5m"}}
+		}, AsDefaults()),
+	)
+
+	require.NoError(t, err)
+	require.NoError(t, NewBundle(Default("http.port", 9090)).Build(builder))
+
+	var ctn di.Container
+	ctn, err = builder.Build()
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = ctn.Close()
+	})
+
+	var v *viper.Viper
+	require.NoError(t, ctn.Resolve(&v))
+	assert.Equal(t, "5m", v.GetString("cache.ttl"), "the later contributed default must win")
+	assert.Equal(t, "lru", v.GetString("cache.policy"))
+	assert.Equal(t, 100, v.GetInt("cache.size"), "config file must take precedence")
+	assert.Equal(t, 9090, v.GetInt("http.port"), "bundle default must take precedence")
+}
@@ -0,0 +1,52 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"log"
+	"strings"
+)
+
+// remap is deprecated key remapped to the new key.
+type remap struct {
+	oldKey  string
+	newKey  string
+	applied bool
+}
+
+// RemapDeprecated option copies value of the deprecated key to the new key on viper provide and config
+// reload, if the old key is set and the new key isn't or has a default value only. The value is set as
+// the new key default, so it never overrides other sources. A warning is logged whenever the deprecated
+// key is set.
+func RemapDeprecated(oldKey, newKey string) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.remaps = append(bundle.remaps, remap{oldKey: oldKey, newKey: newKey})
+	})
+}
+
+// applyRemaps copies values of set deprecated keys to unset new keys. Defaults set by the previous call
+// are reverted first, so a reloaded config is remapped from scratch.
+func (b *Bundle) applyRemaps() {
+	for i := range b.remaps {
+		var r = &b.remaps[i]
+		if r.applied {
+			b.viper.SetDefault(r.newKey, b.defaults[strings.ToLower(r.newKey)])
+			r.applied = false
+		}
+
+		if !b.viper.IsSet(r.oldKey) {
+			continue
+		}
+
+		if b.viper.IsSet(r.newKey) && b.source(strings.ToLower(r.newKey)) != SourceDefault {
+			log.Printf("config key '%s' is deprecated and ignored, '%s' is used instead\n", r.oldKey, r.newKey)
+			continue
+		}
+
+		log.Printf("config key '%s' is deprecated, use '%s' instead\n", r.oldKey, r.newKey)
+		r.applied = true
+		b.viper.SetDefault(r.newKey, b.viper.Get(r.oldKey))
+	}
+}
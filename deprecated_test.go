@@ -0,0 +1,58 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_RemapDeprecated(t *testing.T) {
+	var dir = configDir(t, map[string]string{
+		"config.json": `{"db": {"url": "postgres://old"}}`,
+	})
+
+	var v, err = provide(t, NewBundle(RemapDeprecated("db.url", "db.dsn")), dir)
+	require.NoError(t, err)
+	assert.Equal(t, "postgres://old", v.GetString("db.dsn"), "new key must resolve old key value")
+
+	dir = configDir(t, map[string]string{
+		"config.json": `{"db": {"url": "postgres://old", "dsn": "postgres://new"}}`,
+	})
+
+	v, err = provide(t, NewBundle(RemapDeprecated("db.url", "db.dsn")), dir)
+	require.NoError(t, err)
+	assert.Equal(t, "postgres://new", v.GetString("db.dsn"), "set new key must not be overridden")
+}
+
+func TestBundle_RemapDeprecatedReload(t *testing.T) {
+	var dir = configDir(t, map[string]string{
+		"config.json": `{"db": {"url": "postgres://old"}}`,
+	})
+
+	var b = NewBundle(
+		RemapDeprecated("db.url", "db.dsn"),
+		Default("db.dsn", "postgres://default"),
+		OnChange(func(fsnotify.Event) {}),
+	)
+
+	var v, err = provide(t, b, dir)
+	require.NoError(t, err)
+	assert.Equal(t, "postgres://old", v.GetString("db.dsn"))
+
+	replaceFile(t, v.ConfigFileUsed(), `{"db": {"url": "postgres://reloaded"}}`)
+	require.Eventually(t, func() bool {
+		return b.View().GetString("db.dsn") == "postgres://reloaded"
+	}, 2*time.Second, 10*time.Millisecond, "remap must be re-applied on reload")
+
+	replaceFile(t, v.ConfigFileUsed(), `{}`)
+	require.Eventually(t, func() bool {
+		return b.View().GetString("db.dsn") == "postgres://default"
+	}, 2*time.Second, 10*time.Millisecond, "remap must be reverted once old key is unset")
+}
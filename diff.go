@@ -0,0 +1,142 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+type (
+	// ChangeType is type of key change.
+	ChangeType int
+
+	// KeyChange describes change of key value.
+	KeyChange struct {
+		Key  string
+		Old  interface{}
+		New  interface{}
+		Type ChangeType
+	}
+)
+
+const (
+	// KeyAdded is type of key absent in old settings.
+	KeyAdded ChangeType = iota + 1
+
+	// KeyRemoved is type of key absent in new settings.
+	KeyRemoved
+
+	// KeyModified is type of key with different values in old and new settings.
+	KeyModified
+)
+
+// String implements the fmt.Stringer interface.
+func (t ChangeType) String() string {
+	switch t {
+	case KeyAdded:
+		return "added"
+	case KeyRemoved:
+		return "removed"
+	case KeyModified:
+		return "modified"
+	default:
+		return "unknown"
+	}
+}
+
+// diffStreamBuffer is buffer size of diff stream channel.
+const diffStreamBuffer = 16
+
+// DiffStream returns channel receiving changed keys after every config reload, every call subscribes
+// a new channel. Sends don't block, so a batch is dropped if the channel buffer is full. Config file
+// watching is started if needed, the channel is closed once watching is stopped.
+func (b *Bundle) DiffStream() <-chan []KeyChange {
+	var ch = make(chan []KeyChange, diffStreamBuffer)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.diffStreams = append(b.diffStreams, ch)
+	if b.watchCancel == nil && b.watchCtx != nil {
+		b.watch(b.watchCtx)
+	}
+
+	return ch
+}
+
+// publishDiff sends changes to diff stream subscribers without blocking, the caller must hold the bundle lock.
+func (b *Bundle) publishDiff(changes []KeyChange) {
+	for _, ch := range b.diffStreams {
+		select {
+		case ch <- changes:
+		default:
+		}
+	}
+}
+
+// closeDiffStreams closes channels of diff stream subscribers.
+func (b *Bundle) closeDiffStreams() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, ch := range b.diffStreams {
+		close(ch)
+	}
+
+	b.diffStreams = nil
+}
+
+// WatchKeys option registers handler called for every change of watched keys after the config file
+// was changed and re-read. A key watches its subtree too, so "log" key watches "log.level".
+func WatchKeys(keys []string, handler func(key string, oldVal, newVal interface{})) Option {
+	var watched = make([]string, 0, len(keys))
+	for _, key := range keys {
+		watched = append(watched, strings.ToLower(key))
+	}
+
+	return OnDiff(func(changes []KeyChange) {
+		for _, change := range changes {
+			for _, key := range watched {
+				if change.Key == key || strings.HasPrefix(change.Key, key+keyDelimiter) {
+					handler(change.Key, change.Old, change.New)
+					break
+				}
+			}
+		}
+	})
+}
+
+// Diff compares flattened views of previous and current settings and returns changes sorted by key.
+func (b *Bundle) Diff(previous, current map[string]interface{}) []KeyChange {
+	var (
+		oldFlat = flatten(previous)
+		newFlat = flatten(current)
+		changes = make([]KeyChange, 0)
+	)
+
+	for key, oldValue := range oldFlat {
+		var newValue, ok = newFlat[key]
+		switch {
+		case !ok:
+			changes = append(changes, KeyChange{Key: key, Old: oldValue, Type: KeyRemoved})
+		case !reflect.DeepEqual(oldValue, newValue):
+			changes = append(changes, KeyChange{Key: key, Old: oldValue, New: newValue, Type: KeyModified})
+		}
+	}
+
+	for key, newValue := range newFlat {
+		if _, ok := oldFlat[key]; !ok {
+			changes = append(changes, KeyChange{Key: key, New: newValue, Type: KeyAdded})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+
+	return changes
+}
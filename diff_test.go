@@ -0,0 +1,144 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_Diff(t *testing.T) {
+	var changes = NewBundle().Diff(map[string]interface{}{
+		"db":    map[string]interface{}{"host": "localhost", "port": 5432},
+		"debug": true,
+	}, map[string]interface{}{
+		"db":    map[string]interface{}{"host": "remote", "port": 5432},
+		"level": "info",
+	})
+
+	assert.Equal(t, []KeyChange{
+		{Key: "db.host", Old: "localhost", New: "remote", Type: KeyModified},
+		{Key: "debug", Old: true, Type: KeyRemoved},
+		{Key: "level", New: "info", Type: KeyAdded},
+	}, changes)
+
+	assert.Empty(t, NewBundle().Diff(map[string]interface{}{"a": 1}, map[string]interface{}{"a": 1}))
+}
+
+func TestChangeType_String(t *testing.T) {
+	assert.Equal(t, "added", KeyAdded.String())
+	assert.Equal(t, "removed", KeyRemoved.String())
+	assert.Equal(t, "modified", KeyModified.String())
+	assert.Equal(t, "unknown", ChangeType(0).String())
+}
+
+func TestBundle_OnDiff(t *testing.T) {
+	var (
+		dir     = configDir(t, map[string]string{"config.json": `{"a": 1, "b": 2}`})
+		changes = make(chan []KeyChange, 1)
+		b       = NewBundle(OnDiff(func(in []KeyChange) {
+			changes <- in
+		}))
+	)
+
+	var v, err = provide(t, b, dir)
+	require.NoError(t, err)
+
+	replaceFile(t, v.ConfigFileUsed(), `{"a": 1, "b": 3, "c": 4}`)
+
+	select {
+	case got := <-changes:
+		assert.Equal(t, []KeyChange{
+			{Key: "b", Old: float64(2), New: float64(3), Type: KeyModified},
+			{Key: "c", New: float64(4), Type: KeyAdded},
+		}, got)
+	case <-time.After(2 * time.Second):
+		t.Fatal("diff handler is not called")
+	}
+}
+
+func TestWatchKeys(t *testing.T) {
+	type call struct {
+		key      string
+		old, new interface{}
+	}
+
+	var (
+		dir   = configDir(t, map[string]string{"config.json": `{"log": {"level": "info"}, "name": "app"}`})
+		calls = make(chan call, 4)
+		b     = NewBundle(WatchKeys([]string{"LOG"}, func(key string, oldVal, newVal interface{}) {
+			calls <- call{key: key, old: oldVal, new: newVal}
+		}))
+	)
+
+	var v, err = provide(t, b, dir)
+	require.NoError(t, err)
+
+	replaceFile(t, v.ConfigFileUsed(), `{"log": {"level": "info"}, "name": "changed"}`)
+	require.Eventually(t, func() bool {
+		return b.View().GetString("name") == "changed"
+	}, 2*time.Second, 10*time.Millisecond)
+
+	select {
+	case got := <-calls:
+		t.Fatalf("unwatched key change must not fire callback : %v", got)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	replaceFile(t, v.ConfigFileUsed(), `{"log": {"level": "debug"}, "name": "changed"}`)
+
+	select {
+	case got := <-calls:
+		assert.Equal(t, call{key: "log.level", old: "info", new: "debug"}, got)
+	case <-time.After(2 * time.Second):
+		t.Fatal("watched key change must fire callback")
+	}
+}
+
+func TestBundle_DiffStream(t *testing.T) {
+	var (
+		dir = configDir(t, map[string]string{"config.json": `{"level": "debug", "port": 80}`})
+		b   = NewBundle()
+	)
+
+	var v, err = provide(t, b, dir)
+	require.NoError(t, err)
+
+	var first, second = b.DiffStream(), b.DiffStream()
+
+	var receive = func(ch <-chan []KeyChange) []KeyChange {
+		select {
+		case changes := <-ch:
+			return changes
+		case <-time.After(2 * time.Second):
+			require.Fail(t, "diff batch must arrive")
+			return nil
+		}
+	}
+
+	replaceFile(t, v.ConfigFileUsed(), `{"level": "info", "port": 80}`)
+
+	var expected = []KeyChange{{Key: "level", Old: "debug", New: "info", Type: KeyModified}}
+	assert.Equal(t, expected, receive(first))
+	assert.Equal(t, expected, receive(second), "every subscriber must receive the batch")
+
+	replaceFile(t, v.ConfigFileUsed(), `{"level": "info", "debug": true}`)
+
+	expected = []KeyChange{
+		{Key: "debug", New: true, Type: KeyAdded},
+		{Key: "port", Old: float64(80), Type: KeyRemoved},
+	}
+
+	assert.Equal(t, expected, receive(first))
+	assert.Equal(t, expected, receive(second))
+
+	b.stopWatch()
+
+	var _, ok = <-first
+	assert.False(t, ok, "channel must be closed once watching is stopped")
+}
@@ -0,0 +1,74 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+// embed is definition of config file in file system.
+type embed struct {
+	fsys fs.FS
+	name string
+
+	// envKey is name of env variable with profile, the profile replaces "*" in name.
+	envKey string
+}
+
+// EmbedFS option reads named file from file system (for example embed.FS) as base config,
+// the config file is merged on top of it and becomes optional. The config type is inferred
+// from file extension.
+func EmbedFS(fsys fs.FS, name string) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.embeds = append(bundle.embeds, embed{fsys: fsys, name: name})
+	})
+}
+
+// EmbeddedProfileDefaults option reads file of the profile from file system as base config, the profile
+// is value of the envKey env variable and the file name is the pattern with "*" replaced by the profile,
+// e.g. "defaults.*.yaml". Missing profile or profile file is ignored, otherwise it behaves like EmbedFS.
+func EmbeddedProfileDefaults(fsys fs.FS, pattern string, envKey string) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.embeds = append(bundle.embeds, embed{fsys: fsys, name: pattern, envKey: envKey})
+	})
+}
+
+// readEmbeds reads and merges embedded config files in order.
+func (b *Bundle) readEmbeds() (map[string]interface{}, error) {
+	var settings = make(map[string]interface{})
+	for _, e := range b.embeds {
+		var name = e.name
+		if len(e.envKey) > 0 {
+			var profile, ok = b.lookupEnv(e.envKey)
+			if !ok || len(profile) == 0 {
+				continue
+			}
+
+			name = strings.Replace(name, "*", profile, 1)
+		}
+
+		var data, err = fs.ReadFile(e.fsys, name)
+		if len(e.envKey) > 0 && errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to read embedded config '%s' : %w", name, err)
+		}
+
+		var content map[string]interface{}
+		if content, err = b.parse(name, data); err != nil {
+			return nil, fmt.Errorf("unable to parse embedded config '%s' : %w", name, err)
+		}
+
+		b.addMergeLayer(layerEmbed, name, content)
+		settings = deepMerge(settings, content)
+	}
+
+	return settings, nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_EmbedFS(t *testing.T) {
+	var (
+		fsys = fstest.MapFS{
+			"defaults.yaml": {Data: []byte("db:\n  host: embedded\n  port: 5432\nname: app\n")},
+		}
+		dir = configDir(t, map[string]string{
+			"config.json": `{"db": {"host": "file"}}`,
+		})
+	)
+
+	var b = NewBundle(EmbedFS(fsys, "defaults.yaml"))
+
+	var v, err = provide(t, b, dir)
+	require.NoError(t, err)
+	assert.Equal(t, "file", v.GetString("db.host"), "config file must be merged on top")
+	assert.Equal(t, 5432, v.GetInt("db.port"))
+	assert.Equal(t, "app", v.GetString("name"))
+	assert.NoError(t, b.Ready())
+}
+
+func TestBundle_EmbedFSWithoutConfigFile(t *testing.T) {
+	var (
+		fsys = fstest.MapFS{"defaults.json": {Data: []byte(`{"name": "embedded"}`)}}
+		b    = NewBundle(EmbedFS(fsys, "defaults.json"))
+	)
+
+	var v, err = provide(t, b, t.TempDir())
+	require.NoError(t, err)
+	assert.Equal(t, "embedded", v.GetString("name"))
+	assert.NoError(t, b.Ready(), "config loaded from embedded source must be ready")
+}
+
+func TestBundle_EmbedFSMissing(t *testing.T) {
+	var _, err = provide(t, NewBundle(EmbedFS(fstest.MapFS{}, "defaults.json")), t.TempDir())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unable to read embedded config 'defaults.json'")
+}
+
+func TestBundle_EmbeddedProfileDefaults(t *testing.T) {
+	var (
+		fsys = fstest.MapFS{
+			"defaults.dev.yaml":  {Data: []byte("db:\n  host: dev\nname: dev\n")},
+			"defaults.prod.yaml": {Data: []byte("db:\n  host: prod\n  port: 5432\nname: prod\n")},
+		}
+		dir = configDir(t, map[string]string{
+			"config.json": `{"name": "file"}`,
+		})
+	)
+
+	var tests = []struct {
+		name    string
+		profile string
+		host    string
+	}{
+		{name: "prod", profile: "prod", host: "prod"},
+		{name: "dev", profile: "dev", host: "dev"},
+		{name: "missing file", profile: "stage", host: ""},
+		{name: "missing profile", profile: "", host: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("APP_PROFILE", tt.profile)
+
+			var v, err = provide(t, NewBundle(EmbeddedProfileDefaults(fsys, "defaults.*.yaml", "APP_PROFILE")), dir)
+			require.NoError(t, err)
+			assert.Equal(t, tt.host, v.GetString("db.host"))
+			assert.Equal(t, "file", v.GetString("name"), "config file must be merged on top")
+		})
+	}
+
+	t.Setenv("APP_PROFILE", "prod")
+
+	var v, err = provide(t, NewBundle(EmbeddedProfileDefaults(fsys, "defaults.*.yaml", "APP_PROFILE")), dir)
+	require.NoError(t, err)
+	assert.Equal(t, 5432, v.GetInt("db.port"))
+}
@@ -0,0 +1,48 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"fmt"
+	"strings"
+)
+
+// enum is definition of key with allowed values.
+type enum struct {
+	key     string
+	allowed []string
+}
+
+// RequireEnum option validates on viper provide the key is set to one of allowed values.
+func RequireEnum(key string, allowed ...string) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.enums = append(bundle.enums, enum{key: key, allowed: allowed})
+	})
+}
+
+// GetEnum returns value of key if it is one of allowed values.
+func (b *Bundle) GetEnum(key string, allowed ...string) (string, error) {
+	key = b.nsKey(key)
+	defer b.rlock(key)()
+
+	return b.getEnum(key, allowed...)
+}
+
+// getEnum returns value of the namespaced key if it is one of allowed values.
+func (b *Bundle) getEnum(key string, allowed ...string) (string, error) {
+	if !b.viper.IsSet(key) {
+		return "", fmt.Errorf("unable to get enum '%s' : %w", key, ErrUndefinedKey)
+	}
+
+	var value = b.viper.GetString(key)
+	for _, item := range allowed {
+		if value == item {
+			return value, nil
+		}
+	}
+
+	return "", fmt.Errorf("invalid value %q of '%s', allowed values are [%s]",
+		value, key, strings.Join(allowed, ", "))
+}
@@ -0,0 +1,48 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_GetEnum(t *testing.T) {
+	var dir = configDir(t, map[string]string{
+		"config.json": `{"log": {"format": "json", "level": "verbose"}}`,
+	})
+
+	var b = NewBundle()
+
+	var _, err = provide(t, b, dir)
+	require.NoError(t, err)
+
+	var value string
+	value, err = b.GetEnum("log.format", "json", "text")
+	require.NoError(t, err)
+	assert.Equal(t, "json", value)
+
+	_, err = b.GetEnum("log.level", "debug", "info")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid value "verbose" of 'log.level', allowed values are [debug, info]`)
+
+	_, err = b.GetEnum("log.output", "stdout", "stderr")
+	assert.ErrorIs(t, err, ErrUndefinedKey)
+}
+
+func TestBundle_RequireEnum(t *testing.T) {
+	var dir = configDir(t, map[string]string{
+		"config.json": `{"log": {"format": "xml"}}`,
+	})
+
+	var _, err = provide(t, NewBundle(RequireEnum("log.format", "json", "text")), dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "allowed values are [json, text]")
+
+	_, err = provide(t, NewBundle(RequireEnum("log.format", "json", "xml")), dir)
+	require.NoError(t, err)
+}
@@ -0,0 +1,245 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+// envName returns env variable name of key the same way as viper automatic env does.
+func (b *Bundle) envName(key string) string {
+	var name = strings.ToUpper(key)
+	if len(b.envPrefix) > 0 {
+		name = strings.ToUpper(b.envPrefix + "_" + key)
+	}
+
+	if b.envKeyReplacer != nil {
+		name = b.envKeyReplacer.Replace(name)
+	}
+
+	return name
+}
+
+// lookupEnv retrieves env variable from snapshot if captured, otherwise from process environment.
+func (b *Bundle) lookupEnv(name string) (string, bool) {
+	if b.envSnapshot != nil {
+		var value, ok = b.envSnapshot[name]
+		return value, ok
+	}
+
+	return os.LookupEnv(name)
+}
+
+// applyEnvSnapshot overrides known keys by captured env values, unless bound flag is changed.
+func (b *Bundle) applyEnvSnapshot() {
+	for _, key := range b.viper.AllKeys() {
+		var value, ok = b.envSnapshot[b.envName(key)]
+		if !ok || len(value) == 0 || b.flagChanged(key) {
+			continue
+		}
+
+		b.viper.Set(key, value)
+	}
+}
+
+// applyFoldedEnvs overrides known keys by env values matched case-insensitively, unless the env variable
+// of exact name is present or bound flag is changed.
+func (b *Bundle) applyFoldedEnvs() {
+	var folded = make(map[string]string)
+	for _, pair := range b.environ() {
+		if name, value, ok := strings.Cut(pair, "="); ok {
+			if _, exists := folded[strings.ToUpper(name)]; !exists {
+				folded[strings.ToUpper(name)] = value
+			}
+		}
+	}
+
+	for _, key := range b.viper.AllKeys() {
+		var name = b.envName(key)
+		if _, ok := b.lookupEnv(name); ok || b.flagChanged(key) {
+			continue
+		}
+
+		if value, ok := folded[strings.ToUpper(name)]; ok && len(value) > 0 {
+			b.viper.Set(key, value)
+		}
+	}
+}
+
+// environ returns env variables as "name=value" pairs from snapshot if captured, otherwise from process environment.
+func (b *Bundle) environ() []string {
+	if b.envSnapshot == nil {
+		return os.Environ()
+	}
+
+	var environ = make([]string, 0, len(b.envSnapshot))
+	for name, value := range b.envSnapshot {
+		environ = append(environ, name+"="+value)
+	}
+
+	sort.Strings(environ)
+
+	return environ
+}
+
+// validateEnv checks env values of keys with default value are convertible to the default value type.
+func (b *Bundle) validateEnv() error {
+	var keys = make([]string, 0, len(b.defaults))
+	for key := range b.defaults {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	var messages []string
+	for _, key := range keys {
+		var name = b.envName(key)
+
+		var value, ok = b.lookupEnv(name)
+		if !ok {
+			continue
+		}
+
+		if err := checkType(b.defaults[key], value); err != nil {
+			messages = append(messages, fmt.Sprintf("%s=%q for key '%s' : %s", name, value, key, err))
+		}
+	}
+
+	if len(messages) > 0 {
+		return fmt.Errorf("invalid env values : %s", strings.Join(messages, "; "))
+	}
+
+	return nil
+}
+
+// bindEnvAliases binds alias keys to their env variable followed by the alias env variables,
+// it is called once env prefix is set.
+func (b *Bundle) bindEnvAliases() {
+	for _, alias := range b.envAliases {
+		var name = b.envName(alias.key)
+		if b.envKeyReplacer == nil {
+			name = strings.ReplaceAll(name, keyDelimiter, "_")
+		}
+
+		_ = b.viper.BindEnv(append([]string{alias.key, name}, alias.names...)...)
+	}
+}
+
+// checkType checks value is convertible to the type of def value, only scalar types are checked.
+func checkType(def interface{}, value interface{}) (err error) {
+	switch def.(type) {
+	case bool:
+		_, err = cast.ToBoolE(value)
+	case int, int8, int16, int32, int64:
+		_, err = cast.ToInt64E(value)
+	case uint, uint8, uint16, uint32, uint64:
+		_, err = cast.ToUint64E(value)
+	case float32, float64:
+		_, err = cast.ToFloat64E(value)
+	case time.Duration:
+		if s, ok := value.(string); ok {
+			_, err = time.ParseDuration(s)
+		} else {
+			_, err = cast.ToDurationE(value)
+		}
+	}
+
+	return err
+}
+
+// applySliceEnvs sets comma separated env values of slice env keys as string slice overrides.
+func (b *Bundle) applySliceEnvs() {
+	for _, key := range b.sliceEnvKeys {
+		key = strings.ToLower(key)
+		if b.source(key) != SourceEnv {
+			continue
+		}
+
+		var items = strings.Split(b.viper.GetString(key), ",")
+		for i, item := range items {
+			items[i] = strings.TrimSpace(item)
+		}
+
+		b.viper.Set(key, items)
+	}
+}
+
+// applyBoolEnvs sets env values of bool env keys as bool overrides, unrecognized values are kept.
+func (b *Bundle) applyBoolEnvs() {
+	for _, key := range b.boolEnvKeys {
+		key = strings.ToLower(key)
+		if b.source(key) != SourceEnv {
+			continue
+		}
+
+		switch strings.ToLower(strings.TrimSpace(b.viper.GetString(key))) {
+		case "yes", "on", "1", "true":
+			b.viper.Set(key, true)
+		case "no", "off", "0", "false":
+			b.viper.Set(key, false)
+		}
+	}
+}
+
+// applyEnvPriorities sets keys bound with priority to the value of the first present env variable.
+func (b *Bundle) applyEnvPriorities() {
+	for _, priority := range b.envPriorities {
+		for _, name := range priority.names {
+			if value, ok := b.lookupEnv(name); ok {
+				b.viper.Set(priority.key, value)
+				break
+			}
+		}
+	}
+}
+
+// Environ returns all settings as sorted "PREFIX_KEY=value" pairs suitable for exec.Cmd.Env of child processes.
+// Nested keys are joined by the env key replacer, by underscore if it isn't set, and slice values are comma joined.
+func (b *Bundle) Environ(prefix string) []string {
+	defer b.rlock("")()
+
+	var replacer = b.envKeyReplacer
+	if replacer == nil {
+		replacer = strings.NewReplacer(".", "_")
+	}
+
+	var settings = flatten(b.nsSettings(b.viper.AllSettings()))
+
+	var environ = make([]string, 0, len(settings))
+	for key, value := range settings {
+		var name = strings.ToUpper(key)
+		if len(prefix) > 0 {
+			name = strings.ToUpper(prefix) + "_" + name
+		}
+
+		environ = append(environ, replacer.Replace(name)+"="+envValue(value))
+	}
+
+	sort.Strings(environ)
+
+	return environ
+}
+
+// envValue formats setting value as env variable value, slice items are comma joined.
+func envValue(value interface{}) string {
+	var rv = reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() == reflect.Uint8 {
+		return cast.ToString(value)
+	}
+
+	var values = make([]string, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		values = append(values, cast.ToString(rv.Index(i).Interface()))
+	}
+
+	return strings.Join(values, ",")
+}
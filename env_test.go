@@ -0,0 +1,212 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_EnvStrict(t *testing.T) {
+	t.Setenv("ENV_PORT", "abc")
+	t.Setenv("ENV_DEBUG", "maybe")
+	t.Setenv("ENV_NAME", "app")
+
+	var b = NewBundle(
+		DontUseConfigFile(),
+		EnvStrict(),
+		Default("port", 8080),
+		Default("debug", false),
+		Default("name", "default"),
+	)
+
+	var _, err = provide(t, b, t.TempDir())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `ENV_PORT="abc" for key 'port'`)
+	assert.Contains(t, err.Error(), `ENV_DEBUG="maybe" for key 'debug'`)
+	assert.NotContains(t, err.Error(), "ENV_NAME")
+}
+
+func TestBundle_EnvStrictValid(t *testing.T) {
+	t.Setenv("ENV_PORT", "9090")
+
+	var v, err = provide(t, NewBundle(DontUseConfigFile(), EnvStrict(), Default("port", 8080)), t.TempDir())
+	require.NoError(t, err)
+	assert.Equal(t, 9090, v.GetInt("port"))
+}
+
+func TestBundle_EnvSnapshot(t *testing.T) {
+	t.Setenv("ENV_DB_HOST", "snapshot")
+	t.Setenv("ENV_DB_PORT", "5432")
+
+	var b = NewBundle(EnvSnapshot(), Default("db.port", 0), Default("db.user", "root"))
+
+	t.Setenv("ENV_DB_HOST", "live")
+	t.Setenv("ENV_DB_USER", "live")
+
+	var v, err = provide(t, b, configDir(t, map[string]string{
+		"config.json": `{"db": {"host": "file"}}`,
+	}))
+
+	require.NoError(t, err)
+	assert.Equal(t, "snapshot", v.GetString("db.host"))
+	assert.Equal(t, 5432, v.GetInt("db.port"))
+	assert.Equal(t, "root", v.GetString("db.user"), "env set after snapshot must be ignored")
+
+	var value, ok = b.lookupEnv("ENV_DB_HOST")
+	assert.True(t, ok)
+	assert.Equal(t, "snapshot", value)
+	assert.Contains(t, b.environ(), "ENV_DB_HOST=snapshot")
+}
+
+func TestBundle_SliceEnvKeys(t *testing.T) {
+	t.Setenv("ENV_HOSTS", "a, b,c")
+
+	var dir = configDir(t, map[string]string{
+		"config.json": `{"hosts": ["file"], "ports": ["80", "443"]}`,
+	})
+
+	var b = NewBundle(SliceEnvKeys("hosts", "ports"))
+
+	var v, err = provide(t, b, dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, v.GetStringSlice("hosts"))
+	assert.Equal(t, []string{"80", "443"}, v.GetStringSlice("ports"), "file value must be kept")
+
+	var hosts []string
+	require.NoError(t, b.ReadInto("hosts", &hosts))
+	assert.Len(t, hosts, 3)
+}
+
+func TestBundle_BindEnvPriority(t *testing.T) {
+	var dir = configDir(t, map[string]string{"config.json": `{"port": 80}`})
+
+	var tests = []struct {
+		name string
+		env  map[string]string
+		port int
+	}{
+		{name: "both set", env: map[string]string{"PORT": "8080", "APP_PORT": "9090"}, port: 8080},
+		{name: "second set", env: map[string]string{"APP_PORT": "9090"}, port: 9090},
+		{name: "first empty", env: map[string]string{"PORT": "", "APP_PORT": "9090"}, port: 0},
+		{name: "none set", env: map[string]string{}, port: 80},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			var v, err = provide(t, NewBundle(BindEnvPriority("port", "PORT", "APP_PORT")), dir)
+			require.NoError(t, err)
+			assert.Equal(t, tt.port, v.GetInt("port"))
+		})
+	}
+}
+
+func TestBundle_Environ(t *testing.T) {
+	var dir = configDir(t, map[string]string{
+		"config.json": `{"db": {"host": "localhost", "port": 5432}, "hosts": ["a", "b", "c"], "debug": true}`,
+	})
+
+	var b = NewBundle()
+
+	var _, err = provide(t, b, dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"APP_DB_HOST=localhost",
+		"APP_DB_PORT=5432",
+		"APP_DEBUG=true",
+		"APP_HOSTS=a,b,c",
+	}, b.Environ("app"))
+
+	assert.Contains(t, b.Environ(""), "DB_HOST=localhost")
+}
+
+func TestBundle_BoolEnvKeys(t *testing.T) {
+	var tests = []struct {
+		env  string
+		want interface{}
+	}{
+		{env: "yes", want: true},
+		{env: "ON", want: true},
+		{env: "1", want: true},
+		{env: " True ", want: true},
+		{env: "no", want: false},
+		{env: "Off", want: false},
+		{env: "0", want: false},
+		{env: "false", want: false},
+		{env: "maybe", want: "maybe"},
+	}
+
+	var dir = configDir(t, map[string]string{"config.json": `{"feature": {"enabled": "yes"}}`})
+
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			t.Setenv("ENV_ENABLED", tt.env)
+
+			var v, err = provide(t, NewBundle(BoolEnvKeys("Enabled", "feature.enabled")), dir)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, v.Get("enabled"))
+			assert.Equal(t, "yes", v.Get("feature.enabled"), "file value must be kept")
+		})
+	}
+}
+
+func TestBundle_EnvAlias(t *testing.T) {
+	var dir = configDir(t, map[string]string{"config.json": `{"db": {"dsn": "postgres://file"}}`})
+
+	var tests = []struct {
+		name string
+		env  map[string]string
+		dsn  string
+	}{
+		{name: "legacy", env: map[string]string{"DATABASE_URL": "postgres://legacy"}, dsn: "postgres://legacy"},
+		{name: "canonical", env: map[string]string{"APP_DB_DSN": "postgres://canonical", "DATABASE_URL": "postgres://legacy"}, dsn: "postgres://canonical"},
+		{name: "older", env: map[string]string{"DB_URL": "postgres://older", "DATABASE_URL": "postgres://legacy"}, dsn: "postgres://legacy"},
+		{name: "none", env: map[string]string{}, dsn: "postgres://file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			var b = NewBundle(EnvPrefix("APP"), EnvAlias("DB.Dsn", "DATABASE_URL", "DB_URL"))
+
+			var v, err = provide(t, b, dir)
+			require.NoError(t, err)
+			assert.Equal(t, tt.dsn, v.GetString("db.dsn"))
+			assert.False(t, v.IsSet("database_url"), "config key names must not be affected")
+		})
+	}
+}
+
+func TestBundle_CaseInsensitiveEnvPrefix(t *testing.T) {
+	t.Setenv("app_db_host", "lower")
+	t.Setenv("App_Db_Port", "6432")
+	t.Setenv("APP_DB_NAME", "exact")
+	t.Setenv("app_db_name", "lower")
+
+	var dir = configDir(t, map[string]string{
+		"config.json": `{"db": {"host": "file", "port": 5432, "name": "file"}}`,
+	})
+
+	var v, err = provide(t, NewBundle(EnvPrefix("APP"), CaseInsensitiveEnvPrefix(), Default("db.user", "root")), dir)
+	require.NoError(t, err)
+	assert.Equal(t, "lower", v.GetString("db.host"))
+	assert.Equal(t, 6432, v.GetInt("db.port"))
+	assert.Equal(t, "exact", v.GetString("db.name"), "exact name must win")
+	assert.Equal(t, "root", v.GetString("db.user"))
+
+	v, err = provide(t, NewBundle(EnvPrefix("APP")), dir)
+	require.NoError(t, err)
+	assert.Equal(t, "file", v.GetString("db.host"), "env must be matched case-sensitively by default")
+}
@@ -0,0 +1,55 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// envBase64 is base64 encoded config env variable.
+type envBase64 struct {
+	name       string
+	configType string
+}
+
+// ConfigEnvBase64 option reads base64 encoded config of configType from env variable, as secret managers
+// often inject it. The config is merged under config file, so file values take precedence, and an unset
+// variable is skipped.
+func ConfigEnvBase64(name, configType string) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.configEnvBase64 = append(bundle.configEnvBase64, envBase64{
+			name:       name,
+			configType: configType,
+		})
+	})
+}
+
+// readEnvBase64 decodes and merges base64 encoded config env variables in order.
+func (b *Bundle) readEnvBase64() (map[string]interface{}, error) {
+	var settings = make(map[string]interface{})
+	for _, env := range b.configEnvBase64 {
+		var value, ok = b.lookupEnv(env.name)
+		if !ok {
+			continue
+		}
+
+		var data, err = base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode env '%s' as base64 : %w", env.name, err)
+		}
+
+		var content map[string]interface{}
+		if content, err = b.parse("env."+env.configType, data); err != nil {
+			return nil, fmt.Errorf("unable to parse env '%s' as %s : %w", env.name, env.configType, err)
+		}
+
+		b.addMergeLayer(layerEnvBase64, "env:"+env.name, content)
+		settings = deepMerge(settings, content)
+	}
+
+	return settings, nil
+}
@@ -0,0 +1,44 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_ConfigEnvBase64(t *testing.T) {
+	t.Setenv("APP_CONFIG", base64.StdEncoding.EncodeToString([]byte(`{"db": {"password": "secret", "host": "env"}}`)))
+
+	var v, err = provide(t, NewBundle(ConfigEnvBase64("APP_CONFIG", "json")), t.TempDir())
+	require.NoError(t, err, "config file must be optional")
+	assert.Equal(t, "secret", v.GetString("db.password"))
+	assert.Equal(t, "env", v.GetString("db.host"))
+
+	var dir = configDir(t, map[string]string{"config.json": `{"db": {"host": "file"}}`})
+
+	v, err = provide(t, NewBundle(ConfigEnvBase64("APP_CONFIG", "json")), dir)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", v.GetString("db.password"))
+	assert.Equal(t, "file", v.GetString("db.host"), "config file must take precedence")
+
+	v, err = provide(t, NewBundle(ConfigEnvBase64("APP_MISSING", "json")), dir)
+	require.NoError(t, err, "unset env must be skipped")
+	assert.Equal(t, "file", v.GetString("db.host"))
+}
+
+func TestBundle_ConfigEnvBase64Invalid(t *testing.T) {
+	t.Setenv("APP_INVALID", "not base64!")
+	t.Setenv("APP_MALFORMED", base64.StdEncoding.EncodeToString([]byte(`{"db": `)))
+
+	var _, err = provide(t, NewBundle(ConfigEnvBase64("APP_INVALID", "json")), t.TempDir())
+	assert.ErrorContains(t, err, "unable to decode env 'APP_INVALID' as base64")
+
+	_, err = provide(t, NewBundle(ConfigEnvBase64("APP_MALFORMED", "json")), t.TempDir())
+	assert.ErrorContains(t, err, "unable to parse env 'APP_MALFORMED' as json")
+}
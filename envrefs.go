@@ -0,0 +1,66 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// envRefPattern matches ${VAR} env variable reference.
+var envRefPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// RequireEnvRefs option fails viper provide, if any ${VAR} env variable referenced in string config values
+// is unset, instead of silently producing an empty value once the reference is expanded. The preflight
+// only checks references, values aren't expanded.
+func RequireEnvRefs() Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.requireEnvRefs = true
+	})
+}
+
+// checkEnvRefs checks all env variables referenced in settings are set.
+func (b *Bundle) checkEnvRefs() error {
+	var refs = make(map[string]bool)
+	collectEnvRefs(b.viper.AllSettings(), refs)
+
+	var missing []string
+	for name := range refs {
+		if _, ok := b.lookupEnv(name); !ok {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("env variables [%s] referenced in config are unset", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// collectEnvRefs collects names of env variables referenced in string values.
+func collectEnvRefs(value interface{}, refs map[string]bool) {
+	switch typed := value.(type) {
+	case string:
+		for _, match := range envRefPattern.FindAllStringSubmatch(typed, -1) {
+			refs[match[1]] = true
+		}
+	case map[string]interface{}:
+		for _, item := range typed {
+			collectEnvRefs(item, refs)
+		}
+	case []interface{}:
+		for _, item := range typed {
+			collectEnvRefs(item, refs)
+		}
+	case []string:
+		for _, item := range typed {
+			collectEnvRefs(item, refs)
+		}
+	}
+}
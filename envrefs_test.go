@@ -0,0 +1,45 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_RequireEnvRefs(t *testing.T) {
+	t.Setenv("DB_HOST", "localhost")
+	t.Setenv("DB_USER", "")
+
+	for _, name := range []string{"DB_PASSWORD", "CACHE_URL"} {
+		t.Setenv(name, "")
+		require.NoError(t, os.Unsetenv(name))
+	}
+
+	var dir = configDir(t, map[string]string{
+		"config.json": `{
+			"db": {"dsn": "postgres://${DB_USER}:${DB_PASSWORD}@${DB_HOST}/app"},
+			"cache": {"urls": ["${CACHE_URL}", "redis://${DB_HOST}"]},
+			"literal": "$DB_PASSWORD"
+		}`,
+	})
+
+	var _, err = provide(t, NewBundle(RequireEnvRefs()), dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "env variables [CACHE_URL, DB_PASSWORD] referenced in config are unset")
+
+	v, err := provide(t, NewBundle(), dir)
+	require.NoError(t, err, "references must not be checked by default")
+	assert.Equal(t, "postgres://${DB_USER}:${DB_PASSWORD}@${DB_HOST}/app", v.GetString("db.dsn"))
+
+	t.Setenv("DB_PASSWORD", "secret")
+	t.Setenv("CACHE_URL", "redis://cache")
+
+	_, err = provide(t, NewBundle(RequireEnvRefs()), dir)
+	require.NoError(t, err)
+}
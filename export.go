@@ -0,0 +1,93 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// DebugDump writes all settings as indented json to writer, secret values are redacted.
+func (b *Bundle) DebugDump(w io.Writer) error {
+	var encoder = json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(b.redactedSettings()); err != nil {
+		return fmt.Errorf("unable to dump config : %w", err)
+	}
+
+	return nil
+}
+
+// ExportOptions are options of config export.
+type ExportOptions struct {
+	// Indent is indentation width, zero means format default. Keys are always sorted,
+	// so exports of the same config are byte-identical.
+	Indent int
+}
+
+// Export writes all settings to writer in json, toml or yaml format, values of secret keys are replaced
+// by "***" including secrets nested into maps and slices of any type.
+func (b *Bundle) Export(w io.Writer, format string) error {
+	return b.ExportWith(w, format, ExportOptions{})
+}
+
+// ExportWith writes all settings to writer in json, toml or yaml format with options,
+// secret values are redacted.
+func (b *Bundle) ExportWith(w io.Writer, format string, opts ExportOptions) error {
+	if err := encode(w, format, b.redactedSettings(), opts); err != nil {
+		return fmt.Errorf("unable to export config : %w", err)
+	}
+
+	return nil
+}
+
+// WriteRedacted writes all settings to writer for support bundles, it is the same as Export.
+func (b *Bundle) WriteRedacted(w io.Writer, format string) error { return b.Export(w, format) }
+
+// redactedSettings returns all settings with secret values redacted, it is shared by all config dumps,
+// so they load lazy config and read settings under the bundle read lock alike.
+func (b *Bundle) redactedSettings() map[string]interface{} {
+	defer b.rlock("")()
+	return b.redact(b.viper.AllSettings())
+}
+
+// encode writes settings to writer in json, toml or yaml format.
+func encode(w io.Writer, format string, settings map[string]interface{}, opts ExportOptions) (err error) {
+	switch format {
+	case "json":
+		var encoder = json.NewEncoder(w)
+		if opts.Indent > 0 {
+			encoder.SetIndent("", strings.Repeat(" ", opts.Indent))
+		}
+
+		err = encoder.Encode(settings)
+	case "toml":
+		var encoder = toml.NewEncoder(w)
+		if opts.Indent > 0 {
+			encoder.SetIndentTables(true).SetIndentSymbol(strings.Repeat(" ", opts.Indent))
+		}
+
+		err = encoder.Encode(settings)
+	case "yaml", "yml":
+		var encoder = yaml.NewEncoder(w)
+		if opts.Indent > 0 {
+			encoder.SetIndent(opts.Indent)
+		}
+
+		if err = encoder.Encode(settings); err == nil {
+			err = encoder.Close()
+		}
+	default:
+		return fmt.Errorf("unsupported format '%s'", format)
+	}
+
+	return err
+}
@@ -0,0 +1,100 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_ExportWith(t *testing.T) {
+	var dir = configDir(t, map[string]string{
+		"config.json": `{"zeta": 1, "alpha": {"gamma": "g", "beta": "b"}, "mu": [1, 2]}`,
+	})
+
+	var b = NewBundle()
+
+	var _, err = provide(t, b, dir)
+	require.NoError(t, err)
+
+	for _, format := range []string{"json", "toml", "yaml"} {
+		var first, second bytes.Buffer
+		require.NoError(t, b.ExportWith(&first, format, ExportOptions{Indent: 4}), format)
+
+		for i := 0; i < 10; i++ {
+			second.Reset()
+			require.NoError(t, b.ExportWith(&second, format, ExportOptions{Indent: 4}), format)
+			require.Equal(t, first.String(), second.String(), format)
+		}
+
+		// toml tables follow plain keys, so nested keys are checked only.
+		var (
+			beta  = strings.Index(first.String(), "beta")
+			gamma = strings.Index(first.String(), "gamma")
+			mu    = strings.Index(first.String(), "mu")
+			zeta  = strings.Index(first.String(), "zeta")
+		)
+
+		assert.True(t, beta < gamma && mu < zeta, "keys must be sorted in %s:\n%s", format, first.String())
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, b.ExportWith(&buf, "yaml", ExportOptions{Indent: 4}))
+	assert.Contains(t, buf.String(), "alpha:\n    beta: b\n    gamma: g\n")
+
+	buf.Reset()
+	require.NoError(t, b.ExportWith(&buf, "toml", ExportOptions{Indent: 4}))
+	assert.Contains(t, buf.String(), "[alpha]\n    beta = 'b'\n    gamma = 'g'\n")
+
+	buf.Reset()
+	require.NoError(t, b.ExportWith(&buf, "json", ExportOptions{Indent: 2}))
+	assert.Contains(t, buf.String(), "{\n  \"alpha\": {\n    \"beta\": \"b\",")
+
+	buf.Reset()
+	require.NoError(t, b.Export(&buf, "json"))
+	assert.Equal(t, `{"alpha":{"beta":"b","gamma":"g"},"mu":[1,2],"zeta":1}`+"\n", buf.String())
+
+	assert.Error(t, b.Export(&buf, "ini"))
+}
+
+func TestBundle_ExportSecrets(t *testing.T) {
+	var dir = configDir(t, map[string]string{
+		"config.json": `{
+			"db": {"host": "localhost", "password": "secret-db"},
+			"servers": [{"host": "a", "token": "secret-a"}, {"host": "b", "token": "secret-b"}],
+			"vault": {"keys": ["secret-key-1", "secret-key-2"]}
+		}`,
+	})
+
+	var b = NewBundle(Secret("db.password", "servers.token", "vault.keys", "api.token", "clients.secret"))
+
+	var _, err = provide(t, b, dir)
+	require.NoError(t, err)
+
+	require.NoError(t, b.Set("api", map[string]string{"Token": "secret-api", "url": "https://api"}))
+	require.NoError(t, b.Set("clients", []map[string]interface{}{{"id": "web", "secret": "secret-web"}}))
+
+	for _, format := range []string{"json", "toml", "yaml"} {
+		t.Run(format, func(t *testing.T) {
+			var buf, redacted bytes.Buffer
+			require.NoError(t, b.Export(&buf, format))
+			require.NoError(t, b.WriteRedacted(&redacted, format))
+			assert.Equal(t, buf.String(), redacted.String())
+
+			var out = buf.String()
+			assert.NotContains(t, out, "secret-", "secret values must not leak")
+			assert.Contains(t, out, secretMask)
+			assert.Contains(t, out, "localhost")
+			assert.Contains(t, out, "https://api")
+			assert.Contains(t, out, "web")
+		})
+	}
+
+	assert.ErrorContains(t, b.Export(&bytes.Buffer{}, "xml"), "unable to export config")
+}
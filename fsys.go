@@ -0,0 +1,32 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"github.com/spf13/viper"
+)
+
+// readInConfigFromFS searches and reads config file from the provided fs.FS. Config paths are relative
+// to the filesystem root, the root is searched if no config path is set, app.path is not used.
+func (b *Bundle) readInConfigFromFS() error {
+	var path = b.viper.ConfigFileUsed()
+	if len(path) == 0 {
+		path = b.findConfigFile()
+	}
+
+	if len(path) == 0 {
+		return viper.ConfigFileNotFoundError{}
+	}
+
+	var settings, err = b.readFile(path)
+	if err != nil {
+		return err
+	}
+
+	b.viper.SetConfigFile(path)
+	b.parsedConfig = settings
+
+	return nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"context"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gozix/di"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_FS(t *testing.T) {
+	setArgs(t)
+
+	var builder, err = di.NewBuilder(
+		di.Provide(func() context.Context {
+			return context.Background()
+		}),
+		di.Provide(func() fs.FS {
+			return fstest.MapFS{
+				"config.json": {Data: []byte(`{"name": "embedded"}`)},
+			}
+		}, di.Tags{{Name: TagFS}}),
+	)
+
+	require.NoError(t, err)
+	require.NoError(t, NewBundle().Build(builder))
+
+	var ctn di.Container
+	ctn, err = builder.Build()
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = ctn.Close()
+	})
+
+	var v *viper.Viper
+	require.NoError(t, ctn.Resolve(&v))
+	require.Equal(t, "embedded", v.GetString("name"))
+	require.Equal(t, "config.json", v.ConfigFileUsed())
+}
+
+func TestBundle_FSConfigPath(t *testing.T) {
+	var (
+		dir  = configDir(t, map[string]string{"config.json": `{"name": "os"}`})
+		fsys = fstest.MapFS{
+			"conf/config.json":  {Data: []byte(`{"name": "conf"}`)},
+			"other/config.json": {Data: []byte(`{"name": "other"}`)},
+		}
+	)
+
+	var provideFS = func(b *Bundle, args ...string) (*viper.Viper, error) {
+		setArgs(t, args...)
+
+		var flagSet, err = b.provideFlagSet()
+		require.NoError(t, err)
+
+		var ctx = context.WithValue(context.Background(), "app.path", dir)
+
+		v, closer, err := b.provideViper(ctx, flagSet, fsys, nil)
+		if closer != nil {
+			t.Cleanup(func() {
+				_ = closer()
+			})
+		}
+
+		return v, err
+	}
+
+	var v, err = provideFS(NewBundle(ConfigPath("conf")))
+	require.NoError(t, err)
+	require.Equal(t, "conf", v.GetString("name"), "app.path must not be searched")
+
+	v, err = provideFS(NewBundle(), "--config", "other/config.json")
+	require.NoError(t, err)
+	require.Equal(t, "other", v.GetString("name"))
+
+	_, err = provideFS(NewBundle())
+	require.ErrorAs(t, err, &viper.ConfigFileNotFoundError{})
+}
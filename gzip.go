@@ -0,0 +1,50 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+)
+
+// gzipExt is extension of gzip compressed config files.
+const gzipExt = ".gz"
+
+// isCompressed reports whether file is gzip compressed.
+func isCompressed(path string) bool {
+	return strings.HasSuffix(path, gzipExt)
+}
+
+// decompress returns decompressed gzip data.
+func decompress(data []byte) ([]byte, error) {
+	var reader, err = gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+// compress returns gzip compressed data.
+func compress(data []byte) ([]byte, error) {
+	var (
+		buf    bytes.Buffer
+		writer = gzip.NewWriter(&buf)
+	)
+
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
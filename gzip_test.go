@@ -0,0 +1,64 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// compressed returns gzip compressed content.
+func compressed(t *testing.T, content string) string {
+	t.Helper()
+
+	var data, err = compress([]byte(content))
+	require.NoError(t, err)
+
+	return string(data)
+}
+
+func TestCompress(t *testing.T) {
+	var data, err = decompress([]byte(compressed(t, `{"a":1}`)))
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":1}`, string(data))
+
+	_, err = decompress([]byte("plain"))
+	assert.Error(t, err)
+}
+
+func TestBundle_GzipConfig(t *testing.T) {
+	var dir = configDir(t, map[string]string{
+		"config.json.gz": compressed(t, `{"db": {"host": "gzip"}}`),
+	})
+
+	var v, err = provide(t, NewBundle(), dir)
+	require.NoError(t, err)
+	assert.Equal(t, "gzip", v.GetString("db.host"))
+	assert.Equal(t, filepath.Join(dir, "config.json.gz"), v.ConfigFileUsed())
+}
+
+func TestBundle_GzipConfigFlag(t *testing.T) {
+	var (
+		dir  = configDir(t, map[string]string{"app.yaml.gz": compressed(t, "db:\n  host: yaml\n")})
+		file = filepath.Join(dir, "app.yaml.gz")
+	)
+
+	var v, err = provide(t, NewBundle(), t.TempDir(), "--config", file)
+	require.NoError(t, err)
+	assert.Equal(t, "yaml", v.GetString("db.host"))
+}
+
+func TestBundle_GzipConfigCorrupt(t *testing.T) {
+	var dir = configDir(t, map[string]string{
+		"config.json.gz": `{"db": {"host": "plain"}}`,
+	})
+
+	var _, err = provide(t, NewBundle(), dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "gzip")
+}
@@ -0,0 +1,117 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+// Package hcl2 registers HCL2 config type of viper bundle, viper supports the legacy HCL syntax only.
+// The package is a separate module, because HCL2 requires a newer go version than the bundle,
+// it is imported for side effects:
+//
+//	import _ "github.com/gozix/viper/v3/hcl2"
+package hcl2
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gozix/viper/v3"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// hcl2Type is config type of HCL2 files.
+const hcl2Type = "hcl2"
+
+func init() {
+	viper.RegisterParser(hcl2Type, parseHCL2)
+}
+
+// parseHCL2 parses HCL2 data to settings map, blocks are nested under their type and labels.
+func parseHCL2(data []byte) (map[string]interface{}, error) {
+	var file, diags = hclsyntax.ParseConfig(data, "config."+hcl2Type, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("unable to parse HCL2 config : %w", diags)
+	}
+
+	var body, ok = file.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, fmt.Errorf("unable to parse HCL2 config : unexpected body type %T", file.Body)
+	}
+
+	return hcl2Body(body)
+}
+
+// hcl2Body converts HCL2 body to settings map.
+func hcl2Body(body *hclsyntax.Body) (map[string]interface{}, error) {
+	var settings = make(map[string]interface{}, len(body.Attributes)+len(body.Blocks))
+
+	for name, attr := range body.Attributes {
+		var value, diags = attr.Expr.Value(nil)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("unable to evaluate HCL2 attribute '%s' : %w", name, diags)
+		}
+
+		var data, err = ctyjson.Marshal(value, value.Type())
+		if err != nil {
+			return nil, fmt.Errorf("unable to convert HCL2 attribute '%s' : %w", name, err)
+		}
+
+		var v interface{}
+		if err = json.Unmarshal(data, &v); err != nil {
+			return nil, fmt.Errorf("unable to convert HCL2 attribute '%s' : %w", name, err)
+		}
+
+		settings[name] = v
+	}
+
+	for _, block := range body.Blocks {
+		var child, err = hcl2Body(block.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		var (
+			keys   = append([]string{block.Type}, block.Labels...)
+			target = settings
+		)
+
+		for _, key := range keys[:len(keys)-1] {
+			var next, ok = target[key].(map[string]interface{})
+			if !ok {
+				next = make(map[string]interface{})
+				target[key] = next
+			}
+
+			target = next
+		}
+
+		var last = keys[len(keys)-1]
+		if existing, ok := target[last].(map[string]interface{}); ok {
+			child = deepMerge(existing, child)
+		}
+
+		target[last] = child
+	}
+
+	return settings, nil
+}
+
+// deepMerge merges src into dst recursively, src values take precedence.
+func deepMerge(dst, src map[string]interface{}) map[string]interface{} {
+	if dst == nil {
+		dst = make(map[string]interface{}, len(src))
+	}
+
+	for key, value := range src {
+		var srcMap, srcOk = value.(map[string]interface{})
+		if !srcOk {
+			dst[key] = value
+			continue
+		}
+
+		var dstMap, _ = dst[key].(map[string]interface{})
+		dst[key] = deepMerge(dstMap, srcMap)
+	}
+
+	return dst
+}
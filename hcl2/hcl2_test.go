@@ -0,0 +1,92 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package hcl2
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gozix/di"
+	"github.com/gozix/viper/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHCL2(t *testing.T) {
+	var settings, err = parseHCL2([]byte(`
+name = "app"
+port = 8080
+tags = ["a", "b"]
+
+database "primary" {
+  host = "localhost"
+
+  pool {
+    size = 10
+  }
+}
+
+database "replica" {
+  host = "replica"
+}
+`))
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"name": "app",
+		"port": float64(8080),
+		"tags": []interface{}{"a", "b"},
+		"database": map[string]interface{}{
+			"primary": map[string]interface{}{
+				"host": "localhost",
+				"pool": map[string]interface{}{"size": float64(10)},
+			},
+			"replica": map[string]interface{}{"host": "replica"},
+		},
+	}, settings)
+
+	_, err = parseHCL2([]byte(`name = `))
+	assert.Error(t, err)
+
+	_, err = parseHCL2([]byte(`name = var.missing`))
+	assert.Error(t, err)
+}
+
+func TestBundle_HCL2Config(t *testing.T) {
+	var dir = t.TempDir()
+	require.NoError(t, os.WriteFile(
+		filepath.Join(dir, "config.hcl2"),
+		[]byte("server {\n  listen {\n    port = 8080\n  }\n}\n"),
+		0o644,
+	))
+
+	var orig = os.Args
+	os.Args = []string{"app"}
+
+	t.Cleanup(func() {
+		os.Args = orig
+	})
+
+	var builder, err = di.NewBuilder(di.Provide(func() context.Context {
+		return context.WithValue(context.Background(), "app.path", dir)
+	}))
+
+	require.NoError(t, err)
+	require.NoError(t, viper.NewBundle().Build(builder))
+
+	var ctn di.Container
+	ctn, err = builder.Build()
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = ctn.Close()
+	})
+
+	var config viper.Config
+	require.NoError(t, ctn.Resolve(&config))
+	assert.Equal(t, 8080, config.GetInt("server.listen.port"))
+}
@@ -0,0 +1,47 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"bufio"
+	"bytes"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// yamlLinePattern is pattern of YAML mapping or sequence line.
+var yamlLinePattern = regexp.MustCompile(`^(-\s|[A-Za-z0-9_.\-"']+\s*:(\s|$))`)
+
+// looksLikeYAML reports whether the used JSON config file content looks like YAML.
+func (b *Bundle) looksLikeYAML() bool {
+	var path = b.configFilePath()
+
+	var configType = b.configType
+	if len(configType) == 0 {
+		configType = strings.TrimPrefix(filepath.Ext(path), ".")
+	}
+
+	if configType != "json" {
+		return false
+	}
+
+	var data, err = b.readFileData(path)
+	if err != nil {
+		return false
+	}
+
+	var scanner = bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var line = strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") || line == "---" {
+			continue
+		}
+
+		return yamlLinePattern.MatchString(line)
+	}
+
+	return false
+}
@@ -0,0 +1,53 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_YAMLHint(t *testing.T) {
+	var dir = configDir(t, map[string]string{
+		"config.json": "# app config\n---\nserver:\n  host: localhost\n",
+	})
+
+	var _, err = provide(t, NewBundle(), dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "it looks like YAML content")
+	assert.ErrorAs(t, err, &viper.ConfigParseError{})
+}
+
+func TestBundle_YAMLHintInvalidJSON(t *testing.T) {
+	var dir = configDir(t, map[string]string{
+		"config.json": `{"server": {"host": "localhost",}}`,
+	})
+
+	var _, err = provide(t, NewBundle(), dir)
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "YAML")
+}
+
+func TestBundle_YAMLHintFS(t *testing.T) {
+	setArgs(t)
+
+	var (
+		b    = NewBundle()
+		fsys = fstest.MapFS{"config.json": {Data: []byte("server:\n  host: localhost\n")}}
+	)
+
+	var flagSet, err = b.provideFlagSet()
+	require.NoError(t, err)
+
+	_, _, err = b.provideViper(context.Background(), flagSet, fsys, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `config file "config.json" is not valid JSON, it looks like YAML content`,
+		"config file must be sniffed in the bundle file system")
+}
@@ -0,0 +1,55 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import "strings"
+
+// TrackHistory option remembers up to depth last values of keys, the value is recorded once config
+// is loaded and after every successful reload. Values are available by History.
+func TrackHistory(keys []string, depth int) Option {
+	return optionFunc(func(bundle *Bundle) {
+		if depth < 1 {
+			return
+		}
+
+		bundle.historyDepth = depth
+		for _, key := range keys {
+			bundle.historyKeys = append(bundle.historyKeys, strings.ToLower(key))
+		}
+	})
+}
+
+// History returns remembered values of tracked key from the oldest to the latest one.
+func (b *Bundle) History(key string) []interface{} {
+	defer b.rlock("")()
+
+	var values = b.history[strings.ToLower(key)]
+	if len(values) == 0 {
+		return nil
+	}
+
+	return append([]interface{}(nil), values...)
+}
+
+// recordHistory appends current values of tracked keys to their history, keeping at most depth values.
+// The caller must hold the bundle lock once config watching is started.
+func (b *Bundle) recordHistory() {
+	if len(b.historyKeys) == 0 {
+		return
+	}
+
+	if b.history == nil {
+		b.history = make(map[string][]interface{}, len(b.historyKeys))
+	}
+
+	for _, key := range b.historyKeys {
+		var values = append(b.history[key], b.viper.Get(b.nsKey(key)))
+		if len(values) > b.historyDepth {
+			values = append(values[:0:0], values[len(values)-b.historyDepth:]...)
+		}
+
+		b.history[key] = values
+	}
+}
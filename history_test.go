@@ -0,0 +1,44 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_TrackHistory(t *testing.T) {
+	var (
+		dir     = configDir(t, map[string]string{"config.json": `{"pool": {"size": 0}}`})
+		changes = make(chan struct{}, 10)
+		b       = NewBundle(
+			TrackHistory([]string{"Pool.Size", "pool.missing"}, 3),
+			OnChange(func(fsnotify.Event) { changes <- struct{}{} }),
+		)
+	)
+
+	var v, err = provide(t, b, dir)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{float64(0)}, b.History("pool.size"))
+
+	for i := 1; i <= 4; i++ {
+		replaceFile(t, v.ConfigFileUsed(), fmt.Sprintf(`{"pool": {"size": %d}}`, i))
+
+		select {
+		case <-changes:
+		case <-time.After(2 * time.Second):
+			require.Fail(t, "config must be reloaded")
+		}
+	}
+
+	assert.Equal(t, []interface{}{float64(2), float64(3), float64(4)}, b.History("POOL.SIZE"), "history must be bounded by depth")
+	assert.Equal(t, []interface{}{nil, nil, nil}, b.History("pool.missing"))
+	assert.Nil(t, b.History("untracked"))
+}
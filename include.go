@@ -0,0 +1,95 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+const (
+	// includeKey is include directive key.
+	includeKey = "$include"
+
+	// maxIncludeDepth is max depth of nested includes.
+	maxIncludeDepth = 16
+)
+
+// resolveIncludes replaces include directives of settings by content of included files.
+func (b *Bundle) resolveIncludes(
+	settings map[string]interface{},
+	dir string,
+	visited map[string]bool,
+	depth int,
+) (_ map[string]interface{}, err error) {
+	var result = make(map[string]interface{}, len(settings))
+	for key, value := range settings {
+		if key == includeKey {
+			continue
+		}
+
+		if child, ok := value.(map[string]interface{}); ok {
+			if value, err = b.resolveIncludes(child, dir, visited, depth); err != nil {
+				return nil, err
+			}
+		}
+
+		result[key] = value
+	}
+
+	var directive, ok = settings[includeKey]
+	if !ok {
+		return result, nil
+	}
+
+	var paths []string
+	switch typed := directive.(type) {
+	case string:
+		paths = []string{typed}
+	case []interface{}:
+		for _, item := range typed {
+			var path, ok = item.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid %s directive value : %v", includeKey, directive)
+			}
+
+			paths = append(paths, path)
+		}
+	default:
+		return nil, fmt.Errorf("invalid %s directive value : %v", includeKey, directive)
+	}
+
+	if depth >= maxIncludeDepth {
+		return nil, fmt.Errorf("include depth exceeds %d", maxIncludeDepth)
+	}
+
+	var included = make(map[string]interface{})
+	for _, path := range paths {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+
+		if visited[path] {
+			return nil, fmt.Errorf("include cycle detected : '%s'", path)
+		}
+
+		var content map[string]interface{}
+		if content, err = b.readFile(path); err != nil {
+			return nil, fmt.Errorf("unable to include '%s' : %w", path, err)
+		}
+
+		visited[path] = true
+		content, err = b.resolveIncludes(content, filepath.Dir(path), visited, depth+1)
+		delete(visited, path)
+
+		if err != nil {
+			return nil, err
+		}
+
+		included = deepMerge(included, content)
+	}
+
+	return deepMerge(included, result), nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_Includes(t *testing.T) {
+	var dir = configDir(t, map[string]string{
+		"config.json":        `{"$include": "common/b.yaml", "name": "a", "db": {"$include": "db.json", "port": 5433}}`,
+		"common/b.yaml":      "$include: c.json\nname: b\nlevel: b\n",
+		"common/c.json":      `{"name": "c", "level": "c", "deep": "c"}`,
+		"db.json":            `{"host": "localhost", "port": 5432}`,
+		"unused/ignore.json": `{"ignored": true}`,
+	})
+
+	var v, err = provide(t, NewBundle(Includes()), dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, "a", v.GetString("name"))
+	assert.Equal(t, "b", v.GetString("level"))
+	assert.Equal(t, "c", v.GetString("deep"))
+	assert.Equal(t, "localhost", v.GetString("db.host"))
+	assert.Equal(t, 5433, v.GetInt("db.port"))
+	assert.False(t, v.IsSet("$include"))
+	assert.False(t, v.IsSet("ignored"))
+}
+
+func TestBundle_IncludesCycle(t *testing.T) {
+	var dir = configDir(t, map[string]string{
+		"config.json": `{"$include": "b.json"}`,
+		"b.json":      `{"$include": "c.json"}`,
+		"c.json":      `{"$include": "b.json"}`,
+	})
+
+	var _, err = provide(t, NewBundle(Includes()), dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "include cycle detected")
+}
+
+func TestBundle_IncludesDepth(t *testing.T) {
+	var files = map[string]string{"config.json": `{"$include": "0.json"}`}
+	for i := 0; i <= maxIncludeDepth; i++ {
+		files[fmt.Sprintf("%d.json", i)] = fmt.Sprintf(`{"$include": "%d.json"}`, i+1)
+	}
+
+	var _, err = provide(t, NewBundle(Includes()), configDir(t, files))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), fmt.Sprintf("include depth exceeds %d", maxIncludeDepth))
+}
@@ -0,0 +1,58 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import "github.com/gozix/di"
+
+// InstanceName option names the bundle, so several bundles, e.g. of app and plugin configs, coexist
+// in one container. The name parameterizes the bundle name, tags of provided definitions and flag names:
+// the "plugin" bundle is named "viper.plugin", its viper instance and view are tagged with InstanceTag("plugin")
+// instead of the default tag and its flags are prefixed, e.g. --plugin-config without shorthand.
+func InstanceName(name string) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.instanceName = name
+	})
+}
+
+// InstanceTag returns tag marking viper instance and view of the named bundle.
+func InstanceTag(name string) string {
+	return tagViper + "." + name
+}
+
+// tag returns definition tag of the bundle instance.
+func (b *Bundle) tag(tag string) string {
+	if len(b.instanceName) == 0 {
+		return tag
+	}
+
+	return tag + "." + b.instanceName
+}
+
+// flagName returns flag name of the bundle instance.
+func (b *Bundle) flagName(name string) string {
+	if len(b.instanceName) == 0 {
+		return name
+	}
+
+	return b.instanceName + "-" + name
+}
+
+// viewTags returns tags of the view definition, the view of named bundle is tagged like its viper instance.
+func (b *Bundle) viewTags() di.Tags {
+	if len(b.instanceName) == 0 {
+		return nil
+	}
+
+	return di.Tags{{Name: b.tag(tagViper)}}
+}
+
+// viewConstraint returns constraint of the view dependency, the view of named bundle is resolved by its tag.
+func (b *Bundle) viewConstraint() di.ProvideOption {
+	if len(b.instanceName) == 0 {
+		return di.ProvideOptions()
+	}
+
+	return di.Constraint(0, di.WithTags(b.tag(tagViper)))
+}
@@ -0,0 +1,62 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/gozix/di"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_InstanceName(t *testing.T) {
+	var (
+		dir = configDir(t, map[string]string{
+			"config.json": `{"name": "app"}`,
+			"plugin.json": `{"name": "plugin"}`,
+		})
+		app    = NewBundle()
+		plugin = NewBundle(InstanceName("plugin"), ConfigName("missing"))
+	)
+
+	assert.Equal(t, "viper", app.Name())
+	assert.Equal(t, "viper.plugin", plugin.Name())
+
+	setArgs(t, "--plugin-config", filepath.Join(dir, "plugin.json"))
+
+	var builder, err = di.NewBuilder(di.Provide(func() context.Context {
+		return context.WithValue(context.Background(), "app.path", dir)
+	}))
+
+	require.NoError(t, err)
+	require.NoError(t, app.Build(builder))
+	require.NoError(t, plugin.Build(builder))
+
+	var ctn di.Container
+	ctn, err = builder.Build()
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = ctn.Close()
+	})
+
+	var appViper, pluginViper *viper.Viper
+	require.NoError(t, ctn.Resolve(&appViper, di.WithTags(tagViper)))
+	require.NoError(t, ctn.Resolve(&pluginViper, di.WithTags(InstanceTag("plugin"))))
+	assert.NotSame(t, appViper, pluginViper)
+	assert.Equal(t, "app", appViper.GetString("name"))
+	assert.Equal(t, "plugin", pluginViper.GetString("name"))
+
+	var pluginFlags *pflag.FlagSet
+	require.NoError(t, ctn.Resolve(&pluginFlags, di.WithTags(TagFlagSet+".plugin")))
+	assert.NotNil(t, pluginFlags.Lookup("plugin-config"))
+	assert.Nil(t, pluginFlags.ShorthandLookup("c"), "named bundle flag must not have shorthand")
+	assert.NotNil(t, app.FlagSet().ShorthandLookup("c"))
+}
@@ -0,0 +1,172 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/cast"
+)
+
+// keyType is declared type of key value.
+type keyType struct {
+	key  string
+	kind reflect.Kind
+}
+
+// KeyType option declares the key value is always of bool, int, uint, float or string kind regardless of source,
+// so ambiguous env values like ENV_PORT=8080 are coerced consistently. The value is coerced once config
+// is read and reloaded, viper provide fails if it isn't convertible to the kind.
+func KeyType(key string, kind reflect.Kind) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.keyTypes = append(bundle.keyTypes, keyType{key: strings.ToLower(key), kind: kind})
+	})
+}
+
+// applyKeyTypes coerces values of keys with declared type, the error lists all unconvertible values.
+func (b *Bundle) applyKeyTypes() error {
+	var (
+		keys     = make([]string, 0, len(b.keyTypes))
+		kinds    = make(map[string]reflect.Kind, len(b.keyTypes))
+		messages []string
+	)
+
+	for _, declared := range b.keyTypes {
+		keys = append(keys, declared.key)
+		kinds[declared.key] = declared.kind
+	}
+
+	var err = b.rewriteKeys(keys, func(key string, value interface{}) interface{} {
+		var coerced, err = coerce(value, kinds[key])
+		if err != nil {
+			messages = append(messages, fmt.Sprintf("key '%s' to %s : %s", key, kinds[key], err))
+			return nil
+		}
+
+		return coerced
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if len(messages) > 0 {
+		return fmt.Errorf("unable to coerce %s", strings.Join(messages, "; "))
+	}
+
+	return nil
+}
+
+// rewriteKeys replaces values of the keys by values returned by fn, nil keeps the value as is. Values read
+// from config are replaced in config itself, so they are dropped once the key is removed from reloaded config.
+// Values of other sources, e.g. env variables, are set in the override register and unset before the next
+// rewrite, so fn sees the source value again.
+func (b *Bundle) rewriteKeys(keys []string, fn func(key string, value interface{}) interface{}) error {
+	var config map[string]interface{}
+	for _, key := range keys {
+		if value, ok := b.rewritten[key]; ok {
+			delete(b.rewritten, key)
+
+			// viper has no override removal, the nil override is treated as unset.
+			if reflect.DeepEqual(b.viper.Get(key), value) {
+				b.viper.Set(key, nil)
+			}
+		}
+
+		var (
+			fromConfig = b.isReadFromConfig(key)
+			value      interface{}
+		)
+
+		if fromConfig {
+			value, _ = lookup(b.config, key)
+		} else {
+			value = b.viper.Get(key)
+		}
+
+		if value == nil {
+			continue
+		}
+
+		if value = fn(key, value); value == nil {
+			continue
+		}
+
+		if fromConfig {
+			if config == nil {
+				config = copySettings(b.config)
+			}
+
+			setPath(config, strings.Split(key, keyDelimiter), value)
+			continue
+		}
+
+		b.viper.Set(key, value)
+		if !b.isFilePreferred(key) {
+			if b.rewritten == nil {
+				b.rewritten = make(map[string]interface{})
+			}
+
+			b.rewritten[key] = value
+		}
+	}
+
+	if config == nil {
+		return nil
+	}
+
+	b.config = config
+
+	return b.replaceConfig(config)
+}
+
+// isReadFromConfig reports whether value of the key is read from config, i.e. it isn't overridden by flag, env
+// or file preferred value and isn't default.
+func (b *Bundle) isReadFromConfig(key string) bool {
+	switch b.source(key) {
+	case SourceFile, SourceRemote, SourceConfig:
+		return !b.isFilePreferred(key)
+	default:
+		return false
+	}
+}
+
+// coerce converts value to the kind.
+func coerce(value interface{}, kind reflect.Kind) (interface{}, error) {
+	switch kind {
+	case reflect.Bool:
+		return cast.ToBoolE(value)
+	case reflect.Int:
+		return cast.ToIntE(value)
+	case reflect.Int8:
+		return cast.ToInt8E(value)
+	case reflect.Int16:
+		return cast.ToInt16E(value)
+	case reflect.Int32:
+		return cast.ToInt32E(value)
+	case reflect.Int64:
+		return cast.ToInt64E(value)
+	case reflect.Uint:
+		return cast.ToUintE(value)
+	case reflect.Uint8:
+		return cast.ToUint8E(value)
+	case reflect.Uint16:
+		return cast.ToUint16E(value)
+	case reflect.Uint32:
+		return cast.ToUint32E(value)
+	case reflect.Uint64:
+		return cast.ToUint64E(value)
+	case reflect.Float32:
+		return cast.ToFloat32E(value)
+	case reflect.Float64:
+		return cast.ToFloat64E(value)
+	case reflect.String:
+		return cast.ToStringE(value)
+	default:
+		return nil, fmt.Errorf("unsupported kind %s", kind)
+	}
+}
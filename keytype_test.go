@@ -0,0 +1,93 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_KeyType(t *testing.T) {
+	t.Setenv("ENV_PORT", "8080")
+	t.Setenv("ENV_RATIO", "0.5")
+	t.Setenv("ENV_DEBUG", "1")
+
+	var dir = configDir(t, map[string]string{"config.json": `{"port": 80, "version": 2, "name": "app"}`})
+
+	var v, err = provide(t, NewBundle(
+		KeyType("Port", reflect.Int),
+		KeyType("ratio", reflect.Float64),
+		KeyType("debug", reflect.Bool),
+		KeyType("version", reflect.String),
+		KeyType("missing", reflect.Int),
+	), dir)
+
+	require.NoError(t, err)
+	assert.Equal(t, 8080, v.Get("port"))
+	assert.Equal(t, 0.5, v.Get("ratio"))
+	assert.Equal(t, true, v.Get("debug"))
+	assert.Equal(t, "2", v.Get("version"))
+	assert.Nil(t, v.Get("missing"))
+}
+
+func TestBundle_KeyTypeInvalid(t *testing.T) {
+	t.Setenv("ENV_PORT", "http")
+
+	var dir = configDir(t, map[string]string{"config.json": `{"name": "app", "tags": ["a"]}`})
+
+	var _, err = provide(t, NewBundle(KeyType("port", reflect.Int), KeyType("name", reflect.Map)), dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "key 'port' to int : ")
+	assert.Contains(t, err.Error(), "key 'name' to map : unsupported kind map")
+}
+
+func TestBundle_KeyTypeReload(t *testing.T) {
+	var (
+		dir = configDir(t, map[string]string{"config.json": `{"port": "80"}`})
+		b   = NewBundle(KeyType("port", reflect.Int), OnChange(func(fsnotify.Event) {}))
+	)
+
+	var v, err = provide(t, b, dir)
+	require.NoError(t, err)
+	assert.Equal(t, 80, v.Get("port"))
+
+	replaceFile(t, v.ConfigFileUsed(), `{"port": "8080"}`)
+	require.Eventually(t, func() bool {
+		return b.View().Get("port") == 8080
+	}, 2*time.Second, 10*time.Millisecond, "reloaded value must be coerced")
+
+	replaceFile(t, v.ConfigFileUsed(), `{"name": "app"}`)
+	require.Eventually(t, func() bool {
+		return b.View().GetString("name") == "app"
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.False(t, b.View().IsSet("port"), "removed key must not keep coerced value")
+}
+
+func TestBundle_KeyTypeEnvReload(t *testing.T) {
+	t.Setenv("ENV_PORT", "8080")
+
+	var (
+		dir = configDir(t, map[string]string{"config.json": `{"name": "app"}`})
+		b   = NewBundle(KeyType("port", reflect.Int), OnChange(func(fsnotify.Event) {}))
+	)
+
+	var v, err = provide(t, b, dir)
+	require.NoError(t, err)
+	assert.Equal(t, 8080, v.Get("port"))
+
+	require.NoError(t, os.Unsetenv("ENV_PORT"))
+
+	replaceFile(t, v.ConfigFileUsed(), `{"name": "reloaded", "port": "80"}`)
+	require.Eventually(t, func() bool {
+		return b.View().GetString("name") == "reloaded"
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 80, b.View().Get("port"), "coerced env value must be unset on reload")
+}
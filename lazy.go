@@ -0,0 +1,68 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"context"
+	"io/fs"
+	"log"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// Lazy option defers config reading on provide until the first access through the bundle view
+// or mutators, so commands that don't need config don't read it.
+//
+// Config errors don't fail provide in lazy mode, they are returned by Load and Ready
+// and logged on the first view access. Config is the only config surface provided to the container
+// in lazy mode, resolving viper instance fails with ErrLazyViper, because it isn't loaded on access.
+func Lazy() Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.lazy = true
+	})
+}
+
+// Load reads lazy config once and returns its error, it is no-op if config is not lazy or already loaded.
+func (b *Bundle) Load() error {
+	return b.ensureLoaded()
+}
+
+// ensureLoaded reads lazy config once and returns its error.
+func (b *Bundle) ensureLoaded() error {
+	b.lazyMu.Lock()
+	defer b.lazyMu.Unlock()
+
+	if b.lazyLoad == nil || b.lazyDone {
+		return b.lazyErr
+	}
+
+	b.lazyDone = true
+	if b.lazyErr = b.lazyLoad(); b.lazyErr != nil {
+		log.Printf("error loading config: %v\n", b.lazyErr)
+	}
+
+	return b.lazyErr
+}
+
+// provideLazyView sets config up to be read on the first access and provides the bundle view.
+func (b *Bundle) provideLazyView(
+	ctx context.Context,
+	flagSet *pflag.FlagSet,
+	fsys fs.FS,
+	defaults []Defaults,
+) (*View, func() error, error) {
+	var _, closer, err = b.provideViper(ctx, flagSet, fsys, defaults)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return b.View(), closer, nil
+}
+
+// provideLazyViper fails, because viper instance isn't loaded on access in lazy mode.
+func (b *Bundle) provideLazyViper() (*viper.Viper, error) {
+	return nil, ErrLazyViper
+}
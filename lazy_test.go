@@ -0,0 +1,142 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gozix/di"
+	"github.com/gozix/glue/v3"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_Lazy(t *testing.T) {
+	var (
+		dir = t.TempDir()
+		b   = NewBundle(Lazy())
+	)
+
+	var v, err = provide(t, b, dir)
+	require.NoError(t, err, "missing config file must not fail lazy provide")
+	assert.False(t, v.IsSet("name"))
+	assert.ErrorIs(t, b.Ready(), ErrNotLoaded)
+
+	// the file appears after provide, so it is read on the first access only.
+	writeFile(t, filepath.Join(dir, "config.json"), `{"name": "lazy", "port": 8080}`)
+
+	assert.Equal(t, "lazy", b.View().GetString("name"))
+	assert.Equal(t, 8080, Get(b, "port", 0))
+	assert.NoError(t, b.Load())
+	assert.NoError(t, b.Ready())
+}
+
+func TestBundle_LazyError(t *testing.T) {
+	var (
+		dir = t.TempDir()
+		b   = NewBundle(Lazy())
+	)
+
+	var _, err = provide(t, b, dir)
+	require.NoError(t, err)
+
+	writeFile(t, filepath.Join(dir, "config.json"), `{"name":`)
+
+	assert.Equal(t, "", b.View().GetString("name"))
+
+	err = b.Load()
+	require.Error(t, err)
+	assert.ErrorAs(t, err, &viper.ConfigParseError{})
+	assert.Equal(t, err, b.Ready())
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"name": "fixed"}`), 0o644))
+	assert.Equal(t, err, b.Load(), "lazy config must be read once")
+}
+
+func TestBundle_LazyContainer(t *testing.T) {
+	var (
+		dir = t.TempDir()
+		ctn = buildContainer(t, dir, NewBundle(Lazy()))
+	)
+
+	var v *viper.Viper
+	assert.ErrorIs(t, ctn.Resolve(&v), ErrLazyViper, "viper instance isn't loaded on access")
+
+	var config Config
+	require.NoError(t, ctn.Resolve(&config))
+
+	writeFile(t, filepath.Join(dir, "config.json"), `{"name": "lazy"}`)
+	assert.Equal(t, "lazy", config.GetString("name"))
+
+	ctn = buildContainer(t, dir, NewBundle(Lazy(), InstanceName("plugin"), ConfigName("plugin-config"), PrintConfigFlag("json")))
+	require.NoError(t, ctn.Resolve(&config, di.WithTags(InstanceTag("plugin"))))
+
+	var preRunner glue.PreRunner
+	require.NoError(t, ctn.Resolve(&preRunner), "print config must be set up without viper instance")
+
+	writeFile(t, filepath.Join(dir, "plugin-config.json"), `{"name": "plugin"}`)
+	assert.Equal(t, "plugin", config.GetString("name"))
+}
+
+func TestBundle_LazyReset(t *testing.T) {
+	var (
+		dir = configDir(t, map[string]string{"config.json": `{"name": "first"}`})
+		b   = NewBundle(Lazy())
+	)
+
+	var _, err = provide(t, b, dir)
+	require.NoError(t, err)
+	require.Equal(t, "first", b.View().GetString("name"))
+
+	b.Reset()
+	writeFile(t, filepath.Join(dir, "config.json"), `{"name": "second"}`)
+
+	_, err = provide(t, b, dir)
+	require.NoError(t, err)
+	assert.Equal(t, "second", b.View().GetString("name"), "lazy config must be read again after reset")
+}
+
+func TestBundle_LazyGetters(t *testing.T) {
+	var getters = map[string]func(b *Bundle){
+		"View":     func(b *Bundle) { b.View().AllSettings() },
+		"Get":      func(b *Bundle) { Get(b, "name", "") },
+		"GetSlice": func(b *Bundle) { _, _ = GetSlice[string](b, "name") },
+		"ReadInto": func(b *Bundle) { _ = b.ReadInto("name", new(string)) },
+		"GetBytes": func(b *Bundle) { _, _ = b.GetBytes("name") },
+		"GetEnum":  func(b *Bundle) { _, _ = b.GetEnum("name", "lazy") },
+		"MustGet":  func(b *Bundle) { b.MustGetString("name") },
+		"InConfig": func(b *Bundle) { b.InConfig("name") },
+		"Tree":     func(b *Bundle) { b.Tree() },
+		"Environ":  func(b *Bundle) { b.Environ("APP") },
+		"History":  func(b *Bundle) { b.History("name") },
+		"Export":   func(b *Bundle) { _ = b.DebugDump(io.Discard) },
+		"Unused":   func(b *Bundle) { b.UnusedKeys(&struct{ Name string }{}) },
+		"Shape":    func(b *Bundle) { _ = b.CheckShape(struct{ Name string }{}) },
+		"Scoped":   func(b *Bundle) { b.Scoped("name") },
+		"Set":      func(b *Bundle) { _ = b.Set("other", true) },
+		"Override": func(b *Bundle) { b.Override("other", true) },
+	}
+
+	for name, getter := range getters {
+		t.Run(name, func(t *testing.T) {
+			var (
+				dir = t.TempDir()
+				b   = NewBundle(Lazy())
+			)
+
+			var v, err = provide(t, b, dir)
+			require.NoError(t, err)
+
+			writeFile(t, filepath.Join(dir, "config.json"), `{"name": "lazy"}`)
+			getter(b)
+
+			assert.Equal(t, "lazy", v.GetString("name"))
+		})
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"sort"
+	"strings"
+)
+
+// Merge layer ranks in order of precedence, from the lowest to the highest.
+const (
+	layerEmbed = iota
+	layerEnvBase64
+	layerRemote
+	layerFile
+	layerSubFile
+	layerSource
+	layerSplitKey
+	layerEnvJSON
+)
+
+// mergeLayer is keys of a merged config source.
+type mergeLayer struct {
+	rank int
+	name string
+	keys []string
+}
+
+// ReportMerges option calls fn for each key of a merged config source whose value is overridden by a source
+// of higher precedence: embedded configs, base64 env configs, remote configs, config file, sub files,
+// config sources, split key dirs and JSON env configs. The from and to are names of the overridden and the overriding sources,
+// e.g. file paths. Calls are made in precedence order on every config read.
+func ReportMerges(fn func(key string, from, to string)) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.reportMerges = fn
+	})
+}
+
+// addMergeLayer records keys of merged config source, if merges are reported.
+func (b *Bundle) addMergeLayer(rank int, name string, settings map[string]interface{}) {
+	if b.reportMerges != nil {
+		b.mergeLayers = append(b.mergeLayers, newMergeLayer(rank, name, settings))
+	}
+}
+
+// newMergeLayer returns merge layer of config source settings.
+func newMergeLayer(rank int, name string, settings map[string]interface{}) mergeLayer {
+	var keys = make([]string, 0, len(settings))
+	for key := range flatten(settings) {
+		keys = append(keys, strings.ToLower(key))
+	}
+
+	sort.Strings(keys)
+
+	return mergeLayer{rank: rank, name: name, keys: keys}
+}
+
+// reportMergeLayers reports overridden keys of recorded merge layers and resets them.
+func (b *Bundle) reportMergeLayers() {
+	if b.reportMerges == nil {
+		return
+	}
+
+	var layers = append(b.mergeLayers, b.remoteLayers...)
+	b.mergeLayers = nil
+
+	sort.SliceStable(layers, func(i, j int) bool {
+		return layers[i].rank < layers[j].rank
+	})
+
+	var origins = make(map[string]string)
+	for _, layer := range layers {
+		for _, key := range layer.keys {
+			if from, ok := origins[key]; ok {
+				b.reportMerges(key, from, layer.name)
+			}
+
+			origins[key] = layer.name
+		}
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_ReportMerges(t *testing.T) {
+	type report struct {
+		key, from, to string
+	}
+
+	var (
+		fsys = fstest.MapFS{"defaults.json": {Data: []byte(`{"db": {"host": "embed", "port": 5432}, "name": "embed"}`)}}
+		dir  = configDir(t, map[string]string{
+			"config.json":   `{"db": {"host": "file"}, "debug": true}`,
+			"override.json": `{"db": {"host": "override", "port": 6432}}`,
+		})
+		file     = filepath.Join(dir, "config.json")
+		override = filepath.Join(dir, "override.json")
+		reports  []report
+	)
+
+	var b = NewBundle(
+		EmbedFS(fsys, "defaults.json"),
+		Sources(FileSource(override)),
+		ReportMerges(func(key string, from, to string) {
+			reports = append(reports, report{key: key, from: from, to: to})
+		}),
+	)
+
+	var v, err = provide(t, b, dir)
+	require.NoError(t, err)
+	assert.Equal(t, "override", v.GetString("db.host"))
+	assert.Equal(t, []report{
+		{key: "db.host", from: "defaults.json", to: file},
+		{key: "db.host", from: file, to: override},
+		{key: "db.port", from: "defaults.json", to: override},
+	}, reports)
+}
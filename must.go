@@ -0,0 +1,57 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+// MustGetString returns string value of key, it panics if the key is unset or the value is uncoercible.
+// It is intended for fail-fast startup code, where a missing key is a programming error.
+func (b *Bundle) MustGetString(key string) string {
+	var value, err = cast.ToStringE(b.mustGet(key))
+	if err != nil {
+		panic(fmt.Sprintf("viper: invalid string value of key '%s' : %s", key, err))
+	}
+
+	return value
+}
+
+// MustGetInt returns int value of key, it panics if the key is unset or the value is uncoercible.
+// It is intended for fail-fast startup code, where a missing key is a programming error.
+func (b *Bundle) MustGetInt(key string) int {
+	var value, err = cast.ToIntE(b.mustGet(key))
+	if err != nil {
+		panic(fmt.Sprintf("viper: invalid int value of key '%s' : %s", key, err))
+	}
+
+	return value
+}
+
+// MustGetDuration returns duration value of key, it panics if the key is unset or the value is uncoercible.
+// It is intended for fail-fast startup code, where a missing key is a programming error.
+func (b *Bundle) MustGetDuration(key string) time.Duration {
+	var value, err = cast.ToDurationE(b.mustGet(key))
+	if err != nil {
+		panic(fmt.Sprintf("viper: invalid duration value of key '%s' : %s", key, err))
+	}
+
+	return value
+}
+
+// mustGet returns value of key, it panics if the key is unset.
+func (b *Bundle) mustGet(key string) interface{} {
+	key = b.nsKey(key)
+	defer b.rlock(key)()
+
+	if !b.viper.IsSet(key) {
+		panic(fmt.Sprintf("viper: required key '%s' is unset", key))
+	}
+
+	return b.viper.Get(key)
+}
@@ -0,0 +1,54 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_MustGet(t *testing.T) {
+	var dir = configDir(t, map[string]string{
+		"config.json": `{"name": "app", "port": "8080", "timeout": "5s", "db": {"host": "localhost"}}`,
+	})
+
+	var b = NewBundle()
+
+	var _, err = provide(t, b, dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, "app", b.MustGetString("name"))
+	assert.Equal(t, 8080, b.MustGetInt("port"))
+	assert.Equal(t, 5*time.Second, b.MustGetDuration("timeout"))
+
+	assert.PanicsWithValue(t, "viper: required key 'missing.string' is unset", func() {
+		b.MustGetString("missing.string")
+	})
+
+	assert.PanicsWithValue(t, "viper: required key 'missing.int' is unset", func() {
+		b.MustGetInt("missing.int")
+	})
+
+	assert.PanicsWithValue(t, "viper: required key 'missing.duration' is unset", func() {
+		b.MustGetDuration("missing.duration")
+	})
+
+	assert.Contains(t, panicMessage(func() { b.MustGetInt("name") }), "viper: invalid int value of key 'name'")
+	assert.Contains(t, panicMessage(func() { b.MustGetString("db") }), "viper: invalid string value of key 'db'")
+}
+
+// panicMessage returns panic value of fn, it is empty if fn doesn't panic.
+func panicMessage(fn func()) (message string) {
+	defer func() {
+		message, _ = recover().(string)
+	}()
+
+	fn()
+
+	return ""
+}
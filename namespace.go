@@ -0,0 +1,72 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"strings"
+)
+
+// Namespace option makes keys of bundle getters, view getters, Required and RequireEnum options
+// relative to the prefix, so "db.host" resolves "tenant_a.db.host" under the "tenant_a" namespace.
+// Set, MergeConfigMap, Override and SetBatch mutators take relative keys too. Env variables are resolved
+// by full keys, e.g. ENV_TENANT_A_DB_HOST. Defaults and OverrideMap option use full keys.
+func Namespace(prefix string) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.namespace = strings.ToLower(prefix)
+	})
+}
+
+// nsKey returns full key of the namespace relative key.
+func (b *Bundle) nsKey(key string) string {
+	return joinKey(b.namespace, key)
+}
+
+// nsMap returns settings map nested under the namespace.
+func (b *Bundle) nsMap(settings map[string]interface{}) map[string]interface{} {
+	if len(b.namespace) == 0 {
+		return settings
+	}
+
+	var parts = strings.Split(b.namespace, keyDelimiter)
+	for i := len(parts) - 1; i >= 0; i-- {
+		settings = map[string]interface{}{parts[i]: settings}
+	}
+
+	return settings
+}
+
+// nsKeys returns keys of the namespace relative to it.
+func (b *Bundle) nsKeys(keys []string) []string {
+	if len(b.namespace) == 0 {
+		return keys
+	}
+
+	var (
+		prefix   = b.namespace + keyDelimiter
+		relative = make([]string, 0, len(keys))
+	)
+
+	for _, key := range keys {
+		if strings.HasPrefix(key, prefix) {
+			relative = append(relative, strings.TrimPrefix(key, prefix))
+		}
+	}
+
+	return relative
+}
+
+// nsSettings returns settings subtree of the namespace.
+func (b *Bundle) nsSettings(settings map[string]interface{}) map[string]interface{} {
+	if len(b.namespace) == 0 {
+		return settings
+	}
+
+	var subtree, _ = lookup(settings, b.namespace)
+	if node, ok := subtree.(map[string]interface{}); ok {
+		return node
+	}
+
+	return map[string]interface{}{}
+}
@@ -0,0 +1,100 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_Namespace(t *testing.T) {
+	t.Setenv("ENV_TENANT_A_DB_PORT", "6432")
+
+	var dir = configDir(t, map[string]string{
+		"config.json": `{
+			"tenant_a": {"db": {"host": "a-host", "port": 5432}, "log": {"format": "json"}},
+			"tenant_b": {"db": {"host": "b-host"}}
+		}`,
+	})
+
+	var b = NewBundle(
+		Namespace("Tenant_A"),
+		Required("db.host"),
+		RequireEnum("log.format", "json", "text"),
+	)
+
+	var _, err = provide(t, b, dir)
+	require.NoError(t, err)
+
+	var view = b.View()
+	assert.Equal(t, "a-host", view.GetString("db.host"))
+	assert.Equal(t, 6432, view.GetInt("db.port"), "env must be resolved by full key")
+	assert.Equal(t, "a-host", Get(b, "db.host", ""))
+	assert.ElementsMatch(t, []string{"db.host", "db.port", "log.format"}, view.AllKeys())
+
+	var out struct {
+		DB struct {
+			Host string
+		}
+	}
+
+	require.NoError(t, view.Unmarshal(&out))
+	assert.Equal(t, "a-host", out.DB.Host)
+
+	var format string
+	format, err = b.GetEnum("log.format", "json")
+	require.NoError(t, err)
+	assert.Equal(t, "json", format)
+
+	_, err = provide(t, NewBundle(Namespace("tenant_b"), Required("db.port")), dir)
+	assert.ErrorIs(t, err, ErrUndefinedKey)
+	assert.Contains(t, err.Error(), "tenant_b.db.port")
+}
+
+func TestBundle_NamespaceMutators(t *testing.T) {
+	var (
+		dir = configDir(t, map[string]string{"config.json": `{"tenant": {"db": {"host": "a"}, "mode": "dev"}}`})
+		b   = NewBundle(Namespace("tenant"), Default("tenant.db.port", 5432), RequireEnum("mode", "dev", "prod"))
+	)
+
+	var _, err = provide(t, b, dir)
+	require.NoError(t, err)
+
+	var view = b.View()
+
+	require.NoError(t, b.Set("db.host", "b"))
+	assert.Equal(t, "b", view.GetString("db.host"))
+
+	require.NoError(t, b.MergeConfigMap(map[string]interface{}{"db": map[string]interface{}{"name": "app"}}))
+	assert.Equal(t, "app", view.GetString("db.name"))
+
+	b.Override("db.host", "c")
+	assert.Equal(t, "c", view.GetString("db.host"))
+
+	require.NoError(t, b.SetBatch(map[string]interface{}{"db.port": 6432, "mode": "prod"}))
+	assert.Equal(t, 6432, view.GetInt("db.port"))
+	assert.Equal(t, "prod", view.GetString("mode"))
+
+	err = b.SetBatch(map[string]interface{}{"db.port": "invalid", "mode": "bogus"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "'db.port'", "default of namespaced key must be checked")
+	assert.Contains(t, err.Error(), "'mode'", "enum of namespaced key must be checked")
+	assert.Equal(t, "prod", view.GetString("mode"))
+
+	assert.False(t, b.viper.IsSet("db.host"), "relative keys must not be set outside the namespace")
+}
+
+func TestBundle_nsKeys(t *testing.T) {
+	var b = NewBundle(Namespace("app"))
+
+	assert.Equal(t, "app.db.host", b.nsKey("db.host"))
+	assert.Equal(t, []string{"db.host"}, b.nsKeys([]string{"app.db.host", "other.key", "application.key"}))
+	assert.Equal(t, map[string]interface{}{}, b.nsSettings(map[string]interface{}{"other": 1}))
+	assert.Equal(t, []string{"a"}, NewBundle().nsKeys([]string{"a"}))
+	assert.Equal(t, map[string]interface{}{"a": map[string]interface{}{"b": map[string]interface{}{"key": 1}}},
+		NewBundle(Namespace("a.b")).nsMap(map[string]interface{}{"key": 1}))
+}
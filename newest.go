@@ -0,0 +1,83 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// NewestMatching option uses the newest by modification time file of the dir matching the glob pattern
+// as config file, e.g. "config-*.yaml" for dated config files. The config type is inferred from the file
+// extension. The --config flag takes precedence. If no file matches, config read fails with
+// ConfigFileNotFoundError, unless config file is optional because of embedded config or config sources.
+func NewestMatching(dir, pattern string) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.newestDir = dir
+		bundle.newestPattern = pattern
+	})
+}
+
+// findNewest returns path of the newest file matching the pattern, an empty path means no file matches.
+func (b *Bundle) findNewest() (string, error) {
+	var (
+		glob    = filepath.Join(b.newestDir, b.newestPattern)
+		matches []string
+		err     error
+	)
+
+	if b.fsys != nil {
+		matches, err = fs.Glob(b.fsys, filepath.ToSlash(glob))
+	} else {
+		matches, err = filepath.Glob(glob)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("unable to match config files of pattern '%s' : %w", b.newestPattern, err)
+	}
+
+	var (
+		newest string
+		info   fs.FileInfo
+	)
+
+	for _, match := range matches {
+		var current, err = b.statFile(match)
+		if err != nil || !current.Mode().IsRegular() {
+			continue
+		}
+
+		if info == nil || current.ModTime().After(info.ModTime()) {
+			newest, info = match, current
+		}
+	}
+
+	return newest, nil
+}
+
+// useNewest sets the newest file as config file, the config type is inferred from its extension.
+func (b *Bundle) useNewest(path string) {
+	b.viper.SetConfigFile(path)
+
+	var ext = strings.TrimPrefix(filepath.Ext(strings.TrimSuffix(path, gzipExt)), ".")
+	if _, ok := parsers[ext]; ok || isSupportedExt(ext) {
+		b.configType = ext
+		b.viper.SetConfigType(ext)
+	}
+}
+
+// newNewestNotFoundError returns error describing no file matches the pattern.
+func (b *Bundle) newNewestNotFoundError() error {
+	return &ConfigFileNotFoundError{
+		Name:     b.newestPattern,
+		Paths:    []string{b.newestDir},
+		Patterns: []string{b.newestPattern},
+		err:      viper.ConfigFileNotFoundError{},
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_NewestMatching(t *testing.T) {
+	var (
+		dir = configDir(t, map[string]string{
+			"config-2024-06-01.yaml": "release: june\n",
+			"config-2024-07-01.yaml": "release: july\n",
+			"config-2024-08-01.yaml": "release: august\n",
+			"other-2024-09-01.yaml":  "release: other\n",
+		})
+		now = time.Now()
+	)
+
+	for name, age := range map[string]time.Duration{
+		"config-2024-06-01.yaml": 3 * time.Hour,
+		"config-2024-07-01.yaml": time.Hour,
+		"config-2024-08-01.yaml": 2 * time.Hour,
+	} {
+		require.NoError(t, os.Chtimes(filepath.Join(dir, name), now.Add(-age), now.Add(-age)))
+	}
+
+	var v, err = provide(t, NewBundle(NewestMatching(dir, "config-*.yaml")), t.TempDir())
+	require.NoError(t, err)
+	assert.Equal(t, "july", v.GetString("release"), "the newest by modification time file must be used")
+	assert.Equal(t, filepath.Join(dir, "config-2024-07-01.yaml"), v.ConfigFileUsed())
+
+	var file = filepath.Join(dir, "config-2024-06-01.yaml")
+	v, err = provide(t, NewBundle(NewestMatching(dir, "config-*.yaml"), ConfigType("yaml")), t.TempDir(), "--config", file)
+	require.NoError(t, err)
+	assert.Equal(t, "june", v.GetString("release"), "config flag must take precedence")
+}
+
+func TestBundle_NewestMatchingNotFound(t *testing.T) {
+	var dir = t.TempDir()
+
+	var _, err = provide(t, NewBundle(NewestMatching(dir, "config-*.yaml")), t.TempDir())
+
+	var notFound *ConfigFileNotFoundError
+	require.ErrorAs(t, err, &notFound)
+	assert.Equal(t, []string{dir}, notFound.Paths)
+	assert.Equal(t, []string{"config-*.yaml"}, notFound.Patterns)
+
+	var fsys = fstest.MapFS{"defaults.json": {Data: []byte(`{"release": "embedded"}`)}}
+
+	v, err := provide(t, NewBundle(NewestMatching(dir, "config-*.yaml"), EmbedFS(fsys, "defaults.json")), t.TempDir())
+	require.NoError(t, err, "config file must be optional with embedded config")
+	assert.Equal(t, "embedded", v.GetString("release"))
+}
@@ -0,0 +1,59 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ConfigFileNotFoundError is error, triggered when config file is not found in any search path.
+type ConfigFileNotFoundError struct {
+	// Name is config file base name.
+	Name string
+
+	// Paths are searched directories.
+	Paths []string
+
+	// Patterns are tried file names.
+	Patterns []string
+
+	err error
+}
+
+// Error implements the error interface.
+func (e *ConfigFileNotFoundError) Error() string {
+	return fmt.Sprintf("config file %q not found, searched directories [%s], tried file names [%s]",
+		e.Name, strings.Join(e.Paths, ", "), strings.Join(e.Patterns, ", "))
+}
+
+// Unwrap returns underlying viper.ConfigFileNotFoundError.
+func (e *ConfigFileNotFoundError) Unwrap() error {
+	return e.err
+}
+
+// newConfigFileNotFoundError returns error describing the config file search.
+func (b *Bundle) newConfigFileNotFoundError(err error) error {
+	var names = b.configNames
+	if len(names) == 0 {
+		names = []string{b.configName}
+	}
+
+	var exts = configExts()
+
+	var patterns = make([]string, 0, len(names)*len(exts))
+	for _, name := range names {
+		for _, ext := range exts {
+			patterns = append(patterns, name+"."+ext)
+		}
+	}
+
+	return &ConfigFileNotFoundError{
+		Name:     strings.Join(names, ", "),
+		Paths:    append([]string(nil), b.searchPaths()...),
+		Patterns: patterns,
+		err:      err,
+	}
+}
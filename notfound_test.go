@@ -0,0 +1,34 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_ConfigFileNotFound(t *testing.T) {
+	var (
+		dir   = t.TempDir()
+		extra = t.TempDir()
+	)
+
+	var _, err = provide(t, NewBundle(ConfigPath(extra)), dir)
+	require.Error(t, err)
+
+	var notFound *ConfigFileNotFoundError
+	require.ErrorAs(t, err, &notFound)
+	assert.ErrorAs(t, err, &viper.ConfigFileNotFoundError{})
+	assert.Equal(t, "config", notFound.Name)
+	assert.ElementsMatch(t, []string{dir, extra}, notFound.Paths)
+	assert.Contains(t, notFound.Patterns, "config.json")
+	assert.Contains(t, notFound.Patterns, "config.yaml")
+	assert.Contains(t, err.Error(), dir)
+	assert.Contains(t, err.Error(), extra)
+	assert.Contains(t, err.Error(), "config.json")
+}
@@ -0,0 +1,35 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// PositionalOverrides option sets positional args of key=value form left after flags, e.g. "app db.port=5432",
+// as config overrides on viper provide. Other args, e.g. subcommand names, are ignored.
+//
+// Args are taken from pflag.FlagSet.Args of the bundle flag set, it is parsed with unknown flags allowed,
+// so a value of an unknown flag passed as separate arg, e.g. "--unknown value", is seen as positional.
+// Pass such flags as "--unknown=value" or after "--" terminator, all args after it are positional.
+func PositionalOverrides() Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.argOverrides = true
+	})
+}
+
+// applyPositionalOverrides sets key=value positional args as overrides.
+func (b *Bundle) applyPositionalOverrides(flagSet *pflag.FlagSet) {
+	for _, arg := range flagSet.Args() {
+		var key, value, ok = strings.Cut(arg, "=")
+		if !ok || len(key) == 0 || strings.HasPrefix(key, "-") || strings.ContainsAny(key, " /\\") {
+			continue
+		}
+
+		b.viper.Set(key, value)
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_PositionalOverrides(t *testing.T) {
+	var dir = configDir(t, map[string]string{
+		"config.json": `{"db": {"host": "localhost", "port": 3306}, "name": "app"}`,
+	})
+
+	var v, err = provide(t, NewBundle(PositionalOverrides()), dir,
+		"serve", "db.port=5432", "--unknown=flag", "./path=x", "=empty", "-x=y", "--", "name=positional")
+
+	require.NoError(t, err)
+	assert.Equal(t, 5432, v.GetInt("db.port"))
+	assert.Equal(t, "localhost", v.GetString("db.host"))
+	assert.Equal(t, "positional", v.GetString("name"), "args after terminator must be positional")
+	assert.False(t, v.IsSet("./path"))
+	assert.False(t, v.IsSet("unknown"))
+
+	v, err = provide(t, NewBundle(), dir, "db.port=5432")
+	require.NoError(t, err)
+	assert.Equal(t, 3306, v.GetInt("db.port"), "positional args must be ignored by default")
+}
@@ -0,0 +1,61 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gozix/glue/v3"
+)
+
+// printConfigFlag is name of print effective config flag.
+const printConfigFlag = "print-config"
+
+// PrintConfigFlag option registers --print-config flag, if set, the effective config is printed to stdout
+// in json, toml or yaml format before command is run, secret values are redacted. The command isn't run
+// and app execution fails with ErrConfigPrinted, which the caller should treat as clean exit.
+func PrintConfigFlag(format string) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.printConfigFormat = format
+	})
+}
+
+// providePrintConfig provides persistent pre-runner printing effective config, if the flag is set.
+func (b *Bundle) providePrintConfig(_ *View) glue.PreRunner {
+	return glue.PreRunnerFunc(func(context.Context) error {
+		return b.printConfig(os.Stdout)
+	})
+}
+
+// printConfig prints effective config to writer and returns ErrConfigPrinted, if the flag is set.
+func (b *Bundle) printConfig(w io.Writer) error {
+	if b.flagSet == nil {
+		return nil
+	}
+
+	var name = b.flagName(printConfigFlag)
+
+	var printed, err = b.flagSet.GetBool(name)
+	if err != nil {
+		return fmt.Errorf("unable to get %s flag value : %w", name, err)
+	}
+
+	if !printed {
+		return nil
+	}
+
+	if err = b.Load(); err != nil {
+		return err
+	}
+
+	if err = b.ExportWith(w, b.printConfigFormat, ExportOptions{}); err != nil {
+		return err
+	}
+
+	return ErrConfigPrinted
+}
@@ -0,0 +1,43 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_PrintConfigFlag(t *testing.T) {
+	var dir = configDir(t, map[string]string{
+		"config.json": `{"name": "app", "db": {"password": "secret"}}`,
+	})
+
+	var b = NewBundle(PrintConfigFlag("json"), Secret("db.password"))
+
+	var _, err = provide(t, b, dir, "--print-config")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.ErrorIs(t, b.printConfig(&buf), ErrConfigPrinted)
+	assert.JSONEq(t, `{"name": "app", "db": {"password": "***"}}`, buf.String())
+
+	b = NewBundle(PrintConfigFlag("yaml"))
+
+	_, err = provide(t, b, dir)
+	require.NoError(t, err)
+
+	buf.Reset()
+	require.NoError(t, b.printConfig(&buf), "app must start, if the flag isn't set")
+	assert.Empty(t, buf.String())
+
+	b = NewBundle()
+
+	_, err = provide(t, b, dir)
+	require.NoError(t, err)
+	assert.Nil(t, b.FlagSet().Lookup("print-config"), "flag must be option gated")
+}
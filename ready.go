@@ -0,0 +1,50 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Ready reports config readiness, it returns nil only if config is loaded and all required keys are set.
+// Config is loaded from file, from non-file sources like embedded config, Sources and ConfigEnvBase64
+// if the file isn't found, or without file, if config file is not used.
+func (b *Bundle) Ready() error {
+	if b.lazyErr != nil {
+		return b.lazyErr
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if !b.loaded || (!b.dontUseConfigFile && len(b.replayFrom) == 0 && !b.configFileFound && !b.hasNonFileSource()) {
+		return ErrNotLoaded
+	}
+
+	return b.checkRequired()
+}
+
+// hasNonFileSource reports whether config is read from sources other than config file, so the missing
+// config file is tolerated.
+func (b *Bundle) hasNonFileSource() bool {
+	return len(b.embeds)+len(b.sources)+len(b.configEnvBase64) > 0
+}
+
+// checkRequired checks all required keys are set.
+func (b *Bundle) checkRequired() error {
+	var missing []string
+	for _, key := range b.required {
+		if key = b.nsKey(key); !b.viper.IsSet(key) {
+			missing = append(missing, key)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("required keys [%s] : %w", strings.Join(missing, ", "), ErrUndefinedKey)
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_Ready(t *testing.T) {
+	var (
+		dir = configDir(t, map[string]string{"config.json": `{"db": {"host": "localhost"}}`})
+		b   = NewBundle(Required("db.host"))
+	)
+
+	assert.ErrorIs(t, b.Ready(), ErrNotLoaded)
+
+	var _, err = provide(t, b, dir)
+	require.NoError(t, err)
+	assert.NoError(t, b.Ready())
+}
+
+func TestBundle_ReadyRequired(t *testing.T) {
+	var (
+		dir = configDir(t, map[string]string{"config.json": `{"db": {"host": "localhost"}}`})
+		b   = NewBundle(Required("db.host", "db.port"))
+	)
+
+	var _, err = provide(t, b, dir)
+	require.ErrorIs(t, err, ErrUndefinedKey)
+	assert.Contains(t, err.Error(), "db.port")
+	assert.ErrorIs(t, b.Ready(), ErrNotLoaded)
+}
+
+func TestBundle_ReadyWithoutConfigFile(t *testing.T) {
+	var b = NewBundle(DontUseConfigFile(), Default("name", "app"), Required("name"))
+
+	var _, err = provide(t, b, t.TempDir())
+	require.NoError(t, err)
+	assert.NoError(t, b.Ready())
+}
@@ -0,0 +1,206 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// remoteProvider is remote config provider definition, it implements the viper.RemoteProvider interface.
+type remoteProvider struct {
+	provider string
+	endpoint string
+	path     string
+	key      string
+}
+
+// RemoteProvider option adds remote config provider, see viper.AddRemoteProvider for arguments.
+//
+// Providers are tried in order and the first successfully read config is used, unless RemoteMergeAll
+// option is set. Remote config takes precedence over embedded config and is overridden by config file.
+//
+// Remote features must be enabled by doing a blank import of the github.com/spf13/viper/remote package.
+func RemoteProvider(provider, endpoint, path string) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.remoteProviders = append(bundle.remoteProviders, remoteProvider{
+			provider: provider,
+			endpoint: endpoint,
+			path:     path,
+		})
+	})
+}
+
+// RemoteProviderAt option adds remote config provider which config is merged under the key, so config
+// split across several paths, e.g. "/app/db" and "/app/cache", is composed into one config. An empty key
+// is inferred from the last path segment without extension, e.g. "db" for "/app/db".
+//
+// Unlike RemoteProvider, such providers don't fail over, each of them is read and merged in order,
+// and remote config read fails if any of them is unavailable.
+func RemoteProviderAt(provider, endpoint, path, key string) Option {
+	if len(key) == 0 {
+		key = path[strings.LastIndex(path, "/")+1:]
+		key = strings.TrimSuffix(key, filepath.Ext(key))
+	}
+
+	return optionFunc(func(bundle *Bundle) {
+		bundle.remoteProviders = append(bundle.remoteProviders, remoteProvider{
+			provider: provider,
+			endpoint: endpoint,
+			path:     path,
+			key:      strings.ToLower(key),
+		})
+	})
+}
+
+// RemoteMergeAll option reads all remote providers and merges their configs in order,
+// unavailable providers are skipped.
+func RemoteMergeAll() Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.remoteMergeAll = true
+	})
+}
+
+// RemoteRetry option retries failed remote config read up to attempts times, the delay between
+// attempts starts with backoff and doubles after every attempt.
+func RemoteRetry(attempts int, backoff time.Duration) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.remoteAttempts = attempts
+		bundle.remoteBackoff = backoff
+	})
+}
+
+// Provider implements the viper.RemoteProvider interface.
+func (rp remoteProvider) Provider() string { return rp.provider }
+
+// Endpoint implements the viper.RemoteProvider interface.
+func (rp remoteProvider) Endpoint() string { return rp.endpoint }
+
+// Path implements the viper.RemoteProvider interface.
+func (rp remoteProvider) Path() string { return rp.path }
+
+// SecretKeyring implements the viper.RemoteProvider interface.
+func (rp remoteProvider) SecretKeyring() string { return "" }
+
+// readRemoteConfig reads remote config with retries, the context deadline is respected.
+func (b *Bundle) readRemoteConfig(ctx context.Context) (err error) {
+	var (
+		attempts = b.remoteAttempts
+		backoff  = b.remoteBackoff
+	)
+
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		if b.remoteConfig, b.remoteLayers, err = b.readRemoteProviders(); err == nil {
+			b.touchRemoteKeys()
+			return nil
+		}
+
+		if attempt == attempts {
+			return fmt.Errorf("unable to read remote config after %d attempts : %w", attempt, err)
+		}
+
+		var timer = time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("unable to read remote config after %d attempts : %w", attempt, err)
+		case <-timer.C:
+		}
+
+		backoff *= 2
+	}
+}
+
+// readRemoteProviders reads remote providers in order, failing over to the next one, and returns merged
+// settings with merge layers of read providers. Providers with key are always read and merged under their
+// keys. The error lists failures of all providers, if none of them is available or any provider with key
+// is unavailable.
+func (b *Bundle) readRemoteProviders() (map[string]interface{}, []mergeLayer, error) {
+	if viper.RemoteConfig == nil {
+		return nil, nil, viper.RemoteConfigError("Enable the remote features by doing a blank import of the viper/remote package: '_ github.com/spf13/viper/remote'")
+	}
+
+	var (
+		settings map[string]interface{}
+		failures []string
+		layers   []mergeLayer
+		flatRead bool
+		partErr  bool
+	)
+
+	for _, rp := range b.remoteProviders {
+		if len(rp.key) == 0 && flatRead && !b.remoteMergeAll {
+			continue
+		}
+
+		var content, err = b.readRemoteProvider(rp)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s %s%s : %s", rp.provider, rp.endpoint, rp.path, err))
+			partErr = partErr || len(rp.key) > 0
+			continue
+		}
+
+		if len(rp.key) > 0 {
+			var nested = make(map[string]interface{})
+			setPath(nested, strings.Split(rp.key, keyDelimiter), content)
+			content = nested
+		} else {
+			flatRead = true
+		}
+
+		if b.reportMerges != nil {
+			layers = append(layers, newMergeLayer(layerRemote, rp.provider+" "+rp.endpoint+rp.path, content))
+		}
+
+		settings = deepMerge(settings, content)
+	}
+
+	if settings == nil || partErr {
+		return nil, nil, errors.New(strings.Join(failures, "; "))
+	}
+
+	return settings, layers, nil
+}
+
+// readRemoteProvider reads config of remote provider.
+func (b *Bundle) readRemoteProvider(rp remoteProvider) (map[string]interface{}, error) {
+	if !isSupportedRemoteProvider(rp.provider) {
+		return nil, viper.UnsupportedRemoteProviderError(rp.provider)
+	}
+
+	var reader, err = viper.RemoteConfig.Get(rp)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	if data, err = io.ReadAll(reader); err != nil {
+		return nil, err
+	}
+
+	return b.parse(rp.path, data)
+}
+
+// isSupportedRemoteProvider reports whether viper supports remote provider.
+func isSupportedRemoteProvider(provider string) bool {
+	for _, supported := range viper.SupportedRemoteProviders {
+		if provider == supported {
+			return true
+		}
+	}
+
+	return false
+}
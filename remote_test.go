@@ -0,0 +1,247 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRemote is remote config factory serving values by provider endpoint and path.
+type fakeRemote struct {
+	mu       sync.Mutex
+	values   map[string]string
+	failures map[string]int
+	calls    map[string]int
+}
+
+// newFakeRemote replaces viper remote config factory until the test ends.
+func newFakeRemote(t *testing.T) *fakeRemote {
+	var (
+		orig   = viper.RemoteConfig
+		remote = &fakeRemote{
+			values:   make(map[string]string),
+			failures: make(map[string]int),
+			calls:    make(map[string]int),
+		}
+	)
+
+	viper.RemoteConfig = remote
+	t.Cleanup(func() {
+		viper.RemoteConfig = orig
+	})
+
+	return remote
+}
+
+// set sets value of endpoint path, the first failures reads of it fail.
+func (f *fakeRemote) set(endpoint, path, value string, failures int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.values[endpoint+path] = value
+	f.failures[endpoint+path] = failures
+}
+
+// count returns number of reads of endpoint path.
+func (f *fakeRemote) count(endpoint, path string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.calls[endpoint+path]
+}
+
+func (f *fakeRemote) Get(rp viper.RemoteProvider) (io.Reader, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var key = rp.Endpoint() + rp.Path()
+	f.calls[key]++
+
+	if f.failures[key] > 0 {
+		f.failures[key]--
+		return nil, errors.New("connection refused")
+	}
+
+	var value, ok = f.values[key]
+	if !ok {
+		return nil, errors.New("key not found")
+	}
+
+	return strings.NewReader(value), nil
+}
+
+func (f *fakeRemote) Watch(rp viper.RemoteProvider) (io.Reader, error) {
+	return f.Get(rp)
+}
+
+func (f *fakeRemote) WatchChannel(viper.RemoteProvider) (<-chan *viper.RemoteResponse, chan bool) {
+	return nil, nil
+}
+
+func TestBundle_RemoteRetry(t *testing.T) {
+	var remote = newFakeRemote(t)
+	remote.set("http://127.0.0.1:2379", "/app/config.json", `{"db":{"host":"remote"}}`, 2)
+
+	var b = NewBundle(
+		DontUseConfigFile(),
+		RemoteProvider("etcd3", "http://127.0.0.1:2379", "/app/config.json"),
+		RemoteRetry(3, time.Millisecond),
+	)
+
+	var v, err = provide(t, b, t.TempDir())
+	require.NoError(t, err)
+	assert.Equal(t, "remote", v.GetString("db.host"))
+	assert.Equal(t, 3, remote.count("http://127.0.0.1:2379", "/app/config.json"))
+}
+
+func TestBundle_RemoteRetryExhausted(t *testing.T) {
+	var remote = newFakeRemote(t)
+	remote.set("http://127.0.0.1:2379", "/app/config.json", `{}`, 5)
+
+	var b = NewBundle(
+		DontUseConfigFile(),
+		RemoteProvider("etcd3", "http://127.0.0.1:2379", "/app/config.json"),
+		RemoteRetry(3, time.Millisecond),
+	)
+
+	var _, err = provide(t, b, t.TempDir())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "after 3 attempts")
+	assert.Contains(t, err.Error(), "connection refused")
+	assert.Equal(t, 3, remote.count("http://127.0.0.1:2379", "/app/config.json"))
+}
+
+func TestBundle_RemoteRetryContext(t *testing.T) {
+	var remote = newFakeRemote(t)
+	remote.set("http://127.0.0.1:2379", "/app/config.json", `{}`, 5)
+
+	var (
+		b = NewBundle(
+			RemoteProvider("etcd3", "http://127.0.0.1:2379", "/app/config.json"),
+			RemoteRetry(5, time.Hour),
+		)
+		ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	)
+
+	defer cancel()
+
+	var err = b.readRemoteConfig(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "after 1 attempts")
+	assert.Equal(t, 1, remote.count("http://127.0.0.1:2379", "/app/config.json"))
+}
+
+func TestBundle_RemoteProviderFailover(t *testing.T) {
+	var remote = newFakeRemote(t)
+	remote.set("http://primary:2379", "/app/config.json", `{"db": {"host": "primary"}}`, 1)
+	remote.set("http://backup:2379", "/app/config.json", `{"db": {"host": "backup"}}`, 0)
+
+	var b = NewBundle(
+		DontUseConfigFile(),
+		RemoteProvider("etcd3", "http://primary:2379", "/app/config.json"),
+		RemoteProvider("etcd3", "http://backup:2379", "/app/config.json"),
+	)
+
+	var v, err = provide(t, b, t.TempDir())
+	require.NoError(t, err)
+	assert.Equal(t, "backup", v.GetString("db.host"))
+	assert.Equal(t, 1, remote.count("http://primary:2379", "/app/config.json"))
+	assert.Equal(t, 1, remote.count("http://backup:2379", "/app/config.json"))
+}
+
+func TestBundle_RemoteProviderFirstWins(t *testing.T) {
+	var remote = newFakeRemote(t)
+	remote.set("http://primary:2379", "/app/config.json", `{"db": {"host": "primary"}}`, 0)
+	remote.set("http://backup:2379", "/app/config.json", `{"db": {"host": "backup"}}`, 0)
+
+	var b = NewBundle(
+		DontUseConfigFile(),
+		RemoteProvider("etcd3", "http://primary:2379", "/app/config.json"),
+		RemoteProvider("etcd3", "http://backup:2379", "/app/config.json"),
+	)
+
+	var v, err = provide(t, b, t.TempDir())
+	require.NoError(t, err)
+	assert.Equal(t, "primary", v.GetString("db.host"))
+	assert.Equal(t, 0, remote.count("http://backup:2379", "/app/config.json"), "backup must not be read")
+}
+
+func TestBundle_RemoteMergeAll(t *testing.T) {
+	var remote = newFakeRemote(t)
+	remote.set("http://primary:2379", "/app/config.json", `{"db": {"host": "primary", "port": 5432}}`, 0)
+	remote.set("http://backup:2379", "/app/config.json", `{"db": {"host": "backup"}}`, 0)
+
+	var b = NewBundle(
+		DontUseConfigFile(),
+		RemoteProvider("etcd3", "http://primary:2379", "/app/config.json"),
+		RemoteProvider("etcd3", "http://unavailable:2379", "/app/config.json"),
+		RemoteProvider("etcd3", "http://backup:2379", "/app/config.json"),
+		RemoteMergeAll(),
+	)
+
+	var v, err = provide(t, b, t.TempDir())
+	require.NoError(t, err)
+	assert.Equal(t, "backup", v.GetString("db.host"))
+	assert.Equal(t, 5432, v.GetInt("db.port"))
+}
+
+func TestBundle_RemoteProviderAllFail(t *testing.T) {
+	var remote = newFakeRemote(t)
+	remote.set("http://primary:2379", "/app/config.json", `{}`, 1)
+
+	var b = NewBundle(
+		DontUseConfigFile(),
+		RemoteProvider("etcd3", "http://primary:2379", "/app/config.json"),
+		RemoteProvider("consul", "backup:8500", "/app/config.json"),
+		RemoteProvider("vault", "vault:8200", "/app/config.json"),
+	)
+
+	var _, err = provide(t, b, t.TempDir())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "etcd3 http://primary:2379/app/config.json : connection refused")
+	assert.Contains(t, err.Error(), "consul backup:8500/app/config.json : key not found")
+	assert.Contains(t, err.Error(), "vault vault:8200/app/config.json : Unsupported Remote Provider Type")
+}
+
+func TestBundle_RemoteProviderAt(t *testing.T) {
+	var remote = newFakeRemote(t)
+	remote.set("http://etcd:2379", "/app/config.json", `{"name": "app", "db": {"port": 3306}}`, 0)
+	remote.set("http://etcd:2379", "/app/db.json", `{"host": "db", "port": 5432}`, 0)
+	remote.set("http://etcd:2379", "/app/cache", `{"ttl": "1m"}`, 0)
+
+	var b = NewBundle(
+		DontUseConfigFile(),
+		RemoteProvider("etcd3", "http://etcd:2379", "/app/config.json"),
+		RemoteProviderAt("etcd3", "http://etcd:2379", "/app/db.json", ""),
+		RemoteProviderAt("etcd3", "http://etcd:2379", "/app/cache", "services.Cache"),
+	)
+
+	var v, err = provide(t, b, t.TempDir())
+	require.NoError(t, err)
+	assert.Equal(t, "app", v.GetString("name"))
+	assert.Equal(t, "db", v.GetString("db.host"), "key must be inferred from path")
+	assert.Equal(t, 5432, v.GetInt("db.port"), "later provider must win")
+	assert.Equal(t, "1m", v.GetString("services.cache.ttl"))
+
+	b = NewBundle(
+		DontUseConfigFile(),
+		RemoteProvider("etcd3", "http://etcd:2379", "/app/config.json"),
+		RemoteProviderAt("etcd3", "http://etcd:2379", "/app/missing.json", ""),
+	)
+
+	_, err = provide(t, b, t.TempDir())
+	require.Error(t, err, "unavailable provider with key must fail remote config read")
+	assert.Contains(t, err.Error(), "etcd3 http://etcd:2379/app/missing.json : key not found")
+}
@@ -0,0 +1,120 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"log"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// remoteKeyTTL is remote key cache definition.
+type remoteKeyTTL struct {
+	key    string
+	ttl    time.Duration
+	readAt time.Time
+}
+
+// RemoteKeyTTL option caches remote value of the key and its nested keys, getters of View re-read it from
+// remote providers once the ttl is expired, so slow-changing values are refreshed without watching.
+//
+// The value is re-read only while it isn't overridden by config file or other merged config. A failed
+// re-read is logged and the cached value is used until the ttl is expired again.
+func RemoteKeyTTL(key string, ttl time.Duration) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.remoteKeyTTLs = append(bundle.remoteKeyTTLs, &remoteKeyTTL{
+			key: strings.ToLower(key),
+			ttl: ttl,
+		})
+	})
+}
+
+// touchRemoteKeys marks cached remote values read now.
+func (b *Bundle) touchRemoteKeys() {
+	var now = time.Now()
+	for _, cached := range b.remoteKeyTTLs {
+		cached.readAt = now
+	}
+}
+
+// refreshRemoteKey re-reads remote values of the key, if their ttl is expired. Remote providers are read
+// without the bundle lock, so getters of other keys aren't blocked by the slow read.
+func (b *Bundle) refreshRemoteKey(key string) {
+	if len(b.remoteKeyTTLs) == 0 {
+		return
+	}
+
+	var keys = b.expiredRemoteKeys(strings.ToLower(key))
+	if len(keys) == 0 {
+		return
+	}
+
+	var settings, layers, err = b.readRemoteProviders()
+	if err != nil {
+		log.Printf("error refreshing remote keys [%s]: %v\n", strings.Join(keys, ", "), err)
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.remoteLayers = layers
+	for _, key := range keys {
+		if err = b.replaceRemoteValue(settings, key); err != nil {
+			log.Printf("error refreshing remote key '%s': %v\n", key, err)
+		}
+	}
+}
+
+// expiredRemoteKeys returns cached remote keys of the key with expired ttl and marks them read now.
+func (b *Bundle) expiredRemoteKeys(key string) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.remoteConfig == nil {
+		return nil
+	}
+
+	var keys []string
+	for _, cached := range b.remoteKeyTTLs {
+		if key != cached.key && !strings.HasPrefix(key, cached.key+keyDelimiter) {
+			continue
+		}
+
+		if time.Since(cached.readAt) < cached.ttl {
+			continue
+		}
+
+		cached.readAt = time.Now()
+		keys = append(keys, cached.key)
+	}
+
+	return keys
+}
+
+// replaceRemoteValue replaces the cached remote value of the key in config with the value of re-read
+// settings, the caller must hold the bundle lock.
+func (b *Bundle) replaceRemoteValue(settings map[string]interface{}, key string) error {
+	var value, ok = lookup(settings, key)
+	if !ok {
+		return nil
+	}
+
+	var (
+		path       = strings.Split(key, keyDelimiter)
+		current, _ = lookup(b.config, key)
+		cached, _  = lookup(b.remoteConfig, key)
+	)
+
+	setPath(b.remoteConfig, path, value)
+	if !reflect.DeepEqual(current, cached) || reflect.DeepEqual(current, value) {
+		return nil
+	}
+
+	setPath(b.config, path, value)
+
+	return b.replaceConfig(b.config)
+}
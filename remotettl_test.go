@@ -0,0 +1,110 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_RemoteKeyTTL(t *testing.T) {
+	const (
+		endpoint = "http://etcd:2379"
+		path     = "/app/config.json"
+		ttl      = 100 * time.Millisecond
+	)
+
+	var remote = newFakeRemote(t)
+	remote.set(endpoint, path, `{"feature": {"flag": "old"}, "name": "old"}`, 0)
+
+	var b = NewBundle(
+		DontUseConfigFile(),
+		RemoteProvider("etcd3", endpoint, path),
+		RemoteKeyTTL("Feature", ttl),
+	)
+
+	var _, err = provide(t, b, t.TempDir())
+	require.NoError(t, err)
+	require.Equal(t, 1, remote.count(endpoint, path))
+
+	var view = b.View()
+	remote.set(endpoint, path, `{"feature": {"flag": "new"}, "name": "new"}`, 0)
+
+	assert.Equal(t, "old", view.GetString("feature.flag"), "fresh value must be cached")
+	assert.Equal(t, 1, remote.count(endpoint, path))
+
+	time.Sleep(ttl + 50*time.Millisecond)
+
+	assert.Equal(t, "old", view.GetString("name"), "other keys must not be re-read")
+	assert.Equal(t, 1, remote.count(endpoint, path))
+
+	assert.Equal(t, "new", view.GetString("feature.flag"), "expired value must be re-read")
+	assert.Equal(t, 2, remote.count(endpoint, path))
+
+	assert.Equal(t, "new", view.GetString("feature.flag"))
+	assert.Equal(t, 2, remote.count(endpoint, path), "re-read value must be cached again")
+	assert.Equal(t, "old", view.GetString("name"))
+}
+
+func TestBundle_RemoteKeyTTLUnlocked(t *testing.T) {
+	const (
+		endpoint = "http://etcd:2379"
+		path     = "/app/config.json"
+		ttl      = 50 * time.Millisecond
+	)
+
+	var remote = newFakeRemote(t)
+	remote.set(endpoint, path, `{"feature": {"flag": "old"}, "name": "old"}`, 0)
+
+	var b = NewBundle(DontUseConfigFile(), RemoteProvider("etcd3", endpoint, path), RemoteKeyTTL("feature", ttl))
+
+	var _, err = provide(t, b, t.TempDir())
+	require.NoError(t, err)
+
+	var readAt = func() time.Time {
+		b.mu.RLock()
+		defer b.mu.RUnlock()
+
+		return b.remoteKeyTTLs[0].readAt
+	}
+
+	var (
+		view   = b.View()
+		before = readAt()
+		flag   = make(chan string, 1)
+		name   = make(chan string, 1)
+	)
+
+	remote.set(endpoint, path, `{"feature": {"flag": "new"}, "name": "new"}`, 0)
+	time.Sleep(ttl + 20*time.Millisecond)
+
+	// the remote read blocks until the remote is unlocked.
+	remote.mu.Lock()
+
+	go func() {
+		flag <- view.GetString("feature.flag")
+	}()
+
+	go func() {
+		for readAt() == before {
+			time.Sleep(time.Millisecond)
+		}
+
+		name <- view.GetString("name")
+	}()
+
+	select {
+	case value := <-name:
+		assert.Equal(t, "old", value)
+	case <-time.After(time.Second):
+		assert.Fail(t, "getters must not be blocked by remote read")
+	}
+
+	remote.mu.Unlock()
+	assert.Equal(t, "new", <-flag)
+}
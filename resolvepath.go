@@ -0,0 +1,70 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cast"
+)
+
+// ResolvePathKeys option resolves relative file paths of the keys values against the config file directory
+// instead of the current working directory, e.g. "./certs/server.pem" of "tls.cert". Absolute paths are kept
+// as is, string slice values are resolved item by item.
+//
+// Paths are resolved once config is read and reloaded, if config file is read from OS filesystem.
+func ResolvePathKeys(keys ...string) Option {
+	return optionFunc(func(bundle *Bundle) {
+		for _, key := range keys {
+			bundle.pathKeys = append(bundle.pathKeys, strings.ToLower(key))
+		}
+	})
+}
+
+// resolvePathKeys rewrites relative paths of the keys values to absolute ones based on config file directory.
+func (b *Bundle) resolvePathKeys() error {
+	if !b.configFileFound || b.fsys != nil {
+		return nil
+	}
+
+	var dir, err = filepath.Abs(filepath.Dir(b.viper.ConfigFileUsed()))
+	if err != nil {
+		return fmt.Errorf("unable to resolve config file directory : %w", err)
+	}
+
+	return b.rewriteKeys(b.pathKeys, func(_ string, value interface{}) interface{} {
+		switch value := value.(type) {
+		case string:
+			return resolvePath(dir, value)
+		case []interface{}:
+			var paths = make([]string, 0, len(value))
+			for _, item := range value {
+				paths = append(paths, resolvePath(dir, cast.ToString(item)))
+			}
+
+			return paths
+		case []string:
+			var paths = make([]string, 0, len(value))
+			for _, item := range value {
+				paths = append(paths, resolvePath(dir, item))
+			}
+
+			return paths
+		default:
+			return nil
+		}
+	})
+}
+
+// resolvePath joins relative path with dir, empty and absolute paths are returned as is.
+func resolvePath(dir, path string) string {
+	if len(path) == 0 || filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(dir, path)
+}
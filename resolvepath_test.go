@@ -0,0 +1,52 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_ResolvePathKeys(t *testing.T) {
+	var (
+		dir = configDir(t, map[string]string{
+			"conf/config.json": `{"tls": {"cert": "./certs/server.pem", "key": "/etc/ssl/server.key", "ca": ["ca.pem", "/etc/ssl/ca.pem"], "dh": ""}}`,
+		})
+		conf    = filepath.Join(dir, "conf")
+		changes = make(chan struct{}, 10)
+		b       = NewBundle(
+			ResolvePathKeys("TLS.Cert", "tls.key", "tls.ca", "tls.dh", "tls.missing"),
+			OnChange(func(fsnotify.Event) { changes <- struct{}{} }),
+		)
+	)
+
+	// paths must be resolved against config file directory, not the working directory
+	chdir(t, dir)
+
+	var v, err = provide(t, b, conf)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(conf, "certs", "server.pem"), v.GetString("tls.cert"))
+	assert.Equal(t, "/etc/ssl/server.key", v.GetString("tls.key"), "absolute path must be kept")
+	assert.Equal(t, []string{filepath.Join(conf, "ca.pem"), "/etc/ssl/ca.pem"}, v.GetStringSlice("tls.ca"))
+	assert.Empty(t, v.GetString("tls.dh"))
+	assert.False(t, v.IsSet("tls.missing"))
+
+	replaceFile(t, v.ConfigFileUsed(), `{"tls": {"cert": "certs/reloaded.pem", "ca": ["ca.pem"]}}`)
+
+	select {
+	case <-changes:
+	case <-time.After(2 * time.Second):
+		require.Fail(t, "config must be reloaded")
+	}
+
+	assert.Equal(t, filepath.Join(conf, "certs", "reloaded.pem"), b.View().GetString("tls.cert"))
+	assert.Equal(t, []string{filepath.Join(conf, "ca.pem")}, b.View().GetStringSlice("tls.ca"), "path must be resolved once")
+	assert.False(t, b.View().IsSet("tls.key"), "removed key must not keep resolved path")
+}
@@ -0,0 +1,91 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/viper"
+)
+
+// defaultConfigPerm is permissions of saved config file, when the file doesn't exist.
+const defaultConfigPerm os.FileMode = 0o644
+
+// Save writes all settings to the used config file in its format. The file is written atomically,
+// the content is written to temporary file in the same directory which then replaces the config file.
+func (b *Bundle) Save() error {
+	return b.mutate(func(v *viper.Viper) (err error) {
+		var path = v.ConfigFileUsed()
+		if len(path) == 0 || !b.configFileFound {
+			return fmt.Errorf("unable to save config : %w", ErrNoConfigFile)
+		}
+
+		var buf bytes.Buffer
+		if err = encode(&buf, b.fileType(path), v.AllSettings(), ExportOptions{}); err != nil {
+			return fmt.Errorf("unable to save config : %w", err)
+		}
+
+		var data = buf.Bytes()
+		if isCompressed(path) {
+			if data, err = compress(data); err != nil {
+				return fmt.Errorf("unable to save config : %w", err)
+			}
+		}
+
+		if err = writeFileAtomic(path, data, b.configFilePerm(path)); err != nil {
+			return fmt.Errorf("unable to save config : '%s' : %w", path, err)
+		}
+
+		return nil
+	})
+}
+
+// configFilePerm returns permissions of saved config file.
+func (b *Bundle) configFilePerm(path string) os.FileMode {
+	if b.configPerm != 0 {
+		return b.configPerm
+	}
+
+	if info, err := os.Stat(path); err == nil {
+		return info.Mode().Perm()
+	}
+
+	return defaultConfigPerm
+}
+
+// writeFileAtomic writes data to temporary file and renames it to path.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) (err error) {
+	var file *os.File
+	if file, err = os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*"); err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = os.Remove(file.Name())
+		}
+	}()
+
+	if _, err = file.Write(data); err == nil {
+		err = file.Chmod(perm)
+	}
+
+	if err == nil {
+		err = file.Sync()
+	}
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
@@ -0,0 +1,82 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_Save(t *testing.T) {
+	var (
+		dir  = configDir(t, map[string]string{"config.yaml": "db:\n  host: localhost\n"})
+		file = filepath.Join(dir, "config.yaml")
+	)
+
+	require.NoError(t, os.Chmod(file, 0o600))
+
+	var b = NewBundle(ConfigType("yaml"))
+
+	var _, err = provide(t, b, dir)
+	require.NoError(t, err)
+	require.NoError(t, b.Set("db.port", 5432))
+	require.NoError(t, b.Save())
+
+	var info os.FileInfo
+	info, err = os.Stat(file)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm(), "permissions must be kept")
+
+	var entries []os.DirEntry
+	entries, err = os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary file must be renamed")
+
+	var v *viper.Viper
+	v, err = provide(t, NewBundle(ConfigType("yaml")), dir)
+	require.NoError(t, err)
+	assert.Equal(t, "localhost", v.GetString("db.host"))
+	assert.Equal(t, 5432, v.GetInt("db.port"))
+}
+
+func TestBundle_SaveCompressed(t *testing.T) {
+	var dir = configDir(t, map[string]string{"config.json.gz": compressed(t, `{"name": "app"}`)})
+
+	var b = NewBundle(ConfigPermissions(0o640))
+
+	var _, err = provide(t, b, dir)
+	require.NoError(t, err)
+	require.NoError(t, b.Set("name", "saved"))
+	require.NoError(t, b.Save())
+
+	var info os.FileInfo
+	info, err = os.Stat(filepath.Join(dir, "config.json.gz"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o640), info.Mode().Perm())
+
+	var v *viper.Viper
+	v, err = provide(t, NewBundle(), dir)
+	require.NoError(t, err)
+	assert.Equal(t, "saved", v.GetString("name"))
+}
+
+func TestBundle_SaveWithoutConfigFile(t *testing.T) {
+	var b = NewBundle(DontUseConfigFile())
+
+	var _, err = provide(t, b, t.TempDir())
+	require.NoError(t, err)
+	assert.ErrorIs(t, b.Save(), ErrNoConfigFile)
+
+	b = NewBundle(EmbedFS(fstest.MapFS{"defaults.json": {Data: []byte(`{}`)}}, "defaults.json"))
+	_, err = provide(t, b, t.TempDir())
+	require.NoError(t, err)
+	assert.ErrorIs(t, b.Save(), ErrNoConfigFile)
+}
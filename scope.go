@@ -0,0 +1,52 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import "github.com/spf13/viper"
+
+// Scoped returns bundle wrapping subtree of key, without re-reading config files.
+//
+// The scoped bundle shares env key replacer, automatic env, decode and read-only settings of parent,
+// its env prefix is extended by key, so "host" key of "db" scope is bound to the same env variable
+// as "db.host" key of parent. Secret keys of parent are rebased onto the subtree, so "db.password"
+// secret is "password" secret of "db" scope.
+//
+// The subtree is a snapshot taken by viper.Viper.Sub, the scoped bundle doesn't follow parent reloads
+// and mutations, call Scoped again to get the current subtree.
+func (b *Bundle) Scoped(key string) *Bundle {
+	defer b.rlock("")()
+
+	var sub = b.viper.Sub(key)
+	if sub == nil {
+		sub = viper.New()
+	}
+
+	var scoped = Bundle{
+		viper:             sub,
+		dontUseConfigFile: true,
+		configType:        b.configType,
+		decodeHooks:       b.decodeHooks,
+		tagName:           b.tagName,
+		noDurationHook:    b.noDurationHook,
+		noWeakInput:       b.noWeakInput,
+		readOnly:          b.readOnly,
+		envPrefix:         b.envName(key),
+		envKeyReplacer:    b.envKeyReplacer,
+		automaticEnv:      b.automaticEnv,
+		secrets:           b.scopeSecrets(key),
+		defaults:          make(map[string]interface{}),
+	}
+
+	sub.SetEnvPrefix(scoped.envPrefix)
+	if scoped.envKeyReplacer != nil {
+		sub.SetEnvKeyReplacer(scoped.envKeyReplacer)
+	}
+
+	if scoped.automaticEnv {
+		sub.AutomaticEnv()
+	}
+
+	return &scoped
+}
@@ -0,0 +1,65 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_Scoped(t *testing.T) {
+	t.Setenv("ENV_DB_PORT", "6432")
+
+	var dir = configDir(t, map[string]string{
+		"config.json": `{
+			"db": {"host": "db-host", "port": 5432, "password": "secret"},
+			"cache": {"host": "cache-host", "ttl": "5s"}
+		}`,
+	})
+
+	var b = NewBundle(Secret("db.password"))
+
+	var _, err = provide(t, b, dir)
+	require.NoError(t, err)
+
+	var (
+		db    = b.Scoped("db")
+		cache = b.Scoped("cache")
+	)
+
+	assert.Equal(t, "db-host", db.View().GetString("host"))
+	assert.Equal(t, 6432, db.View().GetInt("port"), "env prefix must be extended by key")
+	assert.Equal(t, "cache-host", cache.View().GetString("host"))
+	assert.False(t, cache.View().IsSet("port"))
+	assert.Equal(t, "5s", Get(cache, "ttl", ""))
+
+	require.NoError(t, db.Set("host", "changed"))
+	assert.Equal(t, "changed", db.View().GetString("host"))
+	assert.Equal(t, "db-host", b.View().GetString("db.host"), "scope must not mutate parent")
+	assert.Equal(t, "cache-host", cache.View().GetString("host"))
+
+	var buf bytes.Buffer
+	require.NoError(t, db.Export(&buf, "json"))
+
+	var settings map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &settings))
+	assert.Equal(t, secretMask, settings["password"], "parent secrets must be rebased onto scope")
+
+	assert.Empty(t, b.Scoped("missing").View().AllSettings())
+}
+
+func TestBundle_scopeSecrets(t *testing.T) {
+	var b = NewBundle(Secret("db.password", "db.replica.*", "cache", "token"))
+
+	assert.ElementsMatch(t, []string{"password", "replica.*"}, b.scopeSecrets("db"))
+	assert.ElementsMatch(t, []string{"*"}, b.scopeSecrets("cache.redis"))
+	assert.ElementsMatch(t, []string{"*"}, b.scopeSecrets("db.replica"))
+	assert.Empty(t, b.scopeSecrets("log"))
+	assert.ElementsMatch(t, []string{"*"}, NewBundle(Secret("*")).scopeSecrets("any"))
+}
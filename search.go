@@ -0,0 +1,132 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// StrictSearch option fails viper provide, if several config files with the same base name and different
+// supported extensions are found in a search directory, instead of silently reading one of them.
+// The check is skipped when config file is set explicitly.
+func StrictSearch() Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.strictSearch = true
+	})
+}
+
+// StrictPaths option fails viper provide, if any config search directory doesn't exist or isn't
+// a directory, instead of silently reporting config file isn't found.
+func StrictPaths() Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.strictPaths = true
+	})
+}
+
+// checkPaths checks every search directory exists and is a directory.
+func (b *Bundle) checkPaths() error {
+	for _, dir := range b.searchPaths() {
+		var info, err = b.statFile(dir)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			return fmt.Errorf("config path '%s' doesn't exist : %w", dir, ErrInvalidConfigPath)
+		case err != nil:
+			return fmt.Errorf("unable to stat config path '%s' : %w", dir, err)
+		case !info.IsDir():
+			return fmt.Errorf("config path '%s' isn't a directory : %w", dir, ErrInvalidConfigPath)
+		}
+	}
+
+	return nil
+}
+
+// checkAmbiguous checks every search directory contains at most one config file of each base name.
+func (b *Bundle) checkAmbiguous() error {
+	if len(b.viper.ConfigFileUsed()) > 0 {
+		return nil
+	}
+
+	var names = b.configNames
+	if len(names) == 0 {
+		names = []string{b.configName}
+	}
+
+	for _, dir := range b.searchPaths() {
+		for _, name := range names {
+			var candidates []string
+			for _, ext := range configExts() {
+				var path = filepath.Join(dir, name+"."+ext)
+				if info, err := b.statFile(path); err == nil && info.Mode().IsRegular() {
+					candidates = append(candidates, path)
+				}
+			}
+
+			if len(candidates) > 1 {
+				return fmt.Errorf("found config files [%s] : %w", strings.Join(candidates, ", "), ErrAmbiguousConfigFile)
+			}
+		}
+	}
+
+	return nil
+}
+
+// findConfigFile returns path of the first found config file in search paths the way viper searches it.
+func (b *Bundle) findConfigFile() string {
+	var names = b.configNames
+	if len(names) == 0 {
+		names = []string{b.configName}
+	}
+
+	for _, name := range names {
+		for _, dir := range b.searchPaths() {
+			for _, ext := range configExts() {
+				var path = filepath.Join(dir, name+"."+ext)
+				if info, err := b.statFile(path); err == nil && info.Mode().IsRegular() {
+					return path
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+// configExts returns extensions of config file candidates, viper supported extensions and extensions
+// of bundle parsers are followed by their gzip compressed variants.
+func configExts() []string {
+	var plain = make([]string, 0, len(viper.SupportedExts)+len(parsers))
+	plain = append(plain, viper.SupportedExts...)
+
+	var registered = make([]string, 0, len(parsers))
+	for ext := range parsers {
+		registered = append(registered, ext)
+	}
+
+	sort.Strings(registered)
+	plain = append(plain, registered...)
+
+	var exts = append([]string(nil), plain...)
+	for _, ext := range plain {
+		exts = append(exts, ext+gzipExt)
+	}
+
+	return exts
+}
+
+// searchPaths returns config search directories.
+func (b *Bundle) searchPaths() []string {
+	if b.fsys != nil && len(b.configPaths) == 0 {
+		return []string{"."}
+	}
+
+	return b.configPaths
+}
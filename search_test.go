@@ -0,0 +1,55 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_StrictSearch(t *testing.T) {
+	var dir = configDir(t, map[string]string{
+		"config.json": `{"a": "json"}`,
+		"config.yaml": `a: yaml`,
+	})
+
+	var _, err = provide(t, NewBundle(StrictSearch()), dir)
+	require.ErrorIs(t, err, ErrAmbiguousConfigFile)
+	require.ErrorContains(t, err, filepath.Join(dir, "config.json"))
+	require.ErrorContains(t, err, filepath.Join(dir, "config.yaml"))
+
+	v, err := provide(t, NewBundle(), dir)
+	require.NoError(t, err)
+	require.Equal(t, "json", v.GetString("a"))
+
+	var file = filepath.Join(dir, "config.json")
+	v, err = provide(t, NewBundle(StrictSearch()), dir, "--config", file)
+	require.NoError(t, err)
+	require.Equal(t, file, v.ConfigFileUsed())
+}
+
+func TestBundle_StrictPaths(t *testing.T) {
+	var (
+		dir     = configDir(t, map[string]string{"config.json": `{}`, "file.json": `{}`})
+		missing = filepath.Join(dir, "missing")
+		file    = filepath.Join(dir, "file.json")
+	)
+
+	var _, err = provide(t, NewBundle(StrictPaths(), ConfigPath(missing)), dir)
+	require.ErrorIs(t, err, ErrInvalidConfigPath)
+	require.ErrorContains(t, err, "config path '"+missing+"' doesn't exist")
+
+	_, err = provide(t, NewBundle(StrictPaths(), ConfigPath(file)), dir)
+	require.ErrorIs(t, err, ErrInvalidConfigPath)
+	require.ErrorContains(t, err, "config path '"+file+"' isn't a directory")
+
+	_, err = provide(t, NewBundle(ConfigPath(missing)), dir)
+	require.NoError(t, err, "missing path must be ignored by default")
+
+	_, err = provide(t, NewBundle(StrictPaths(), ConfigPath(dir)), dir)
+	require.NoError(t, err)
+}
@@ -0,0 +1,144 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	// secretMask is replacement of secret values.
+	secretMask = "***"
+
+	// secretAll is secret key marking all keys.
+	secretAll = "*"
+)
+
+// Secret option marks keys as secret, so their values are redacted by DebugDump and Export.
+//
+// A key marks the whole subtree as secret, for example "db" redacts "db.user" and "db.password".
+// A key suffixed with ".*" marks all nested keys, for example "db.*" redacts "db.password",
+// but keeps the "db" node itself. The "*" key marks all keys.
+func Secret(keys ...string) Option {
+	return optionFunc(func(bundle *Bundle) {
+		for _, key := range keys {
+			bundle.secrets = append(bundle.secrets, strings.ToLower(key))
+		}
+	})
+}
+
+// isSecret reports whether the key is marked as secret.
+func (b *Bundle) isSecret(key string) bool {
+	key = strings.ToLower(key)
+	for _, secret := range b.secrets {
+		if secret == secretAll {
+			return true
+		}
+
+		if strings.HasSuffix(secret, ".*") {
+			if strings.HasPrefix(key, strings.TrimSuffix(secret, "*")) {
+				return true
+			}
+
+			continue
+		}
+
+		if key == secret || strings.HasPrefix(key, secret+keyDelimiter) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// scopeSecrets returns secret keys rebased onto subtree of the key, secrets outside the subtree are dropped
+// and secrets covering the whole subtree mark all its keys.
+func (b *Bundle) scopeSecrets(key string) []string {
+	var (
+		prefix  = strings.ToLower(key) + keyDelimiter
+		secrets []string
+	)
+
+	for _, secret := range b.secrets {
+		var base = strings.TrimSuffix(secret, ".*")
+		switch {
+		case secret == secretAll || strings.HasPrefix(prefix, base+keyDelimiter):
+			secrets = append(secrets, secretAll)
+		case strings.HasPrefix(base, prefix):
+			secrets = append(secrets, strings.TrimPrefix(secret, prefix))
+		}
+	}
+
+	return secrets
+}
+
+// redact returns deep copy of settings with secret values replaced by mask.
+func (b *Bundle) redact(settings map[string]interface{}) map[string]interface{} {
+	return b.redactMap("", settings)
+}
+
+func (b *Bundle) redactMap(prefix string, in map[string]interface{}) map[string]interface{} {
+	var out = make(map[string]interface{}, len(in))
+	for name, value := range in {
+		var key = name
+		if len(prefix) > 0 {
+			key = prefix + keyDelimiter + name
+		}
+
+		out[name] = b.redactValue(key, value)
+	}
+
+	return out
+}
+
+func (b *Bundle) redactValue(key string, value interface{}) interface{} {
+	if b.isSecret(key) {
+		return secretMask
+	}
+
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		return b.redactMap(key, typed)
+	case []interface{}:
+		var out = make([]interface{}, 0, len(typed))
+		for _, item := range typed {
+			out = append(out, b.redactValue(key, item))
+		}
+
+		return out
+	default:
+		return b.redactReflect(key, value)
+	}
+}
+
+// redactReflect redacts typed maps and slices, e.g. map[string]string or []map[string]interface{} values set
+// at runtime, which are converted to generic ones, so secret values nested into them don't leak.
+func (b *Bundle) redactReflect(key string, value interface{}) interface{} {
+	var rv = reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Map:
+		var in = make(map[string]interface{}, rv.Len())
+		for iter := rv.MapRange(); iter.Next(); {
+			in[strings.ToLower(fmt.Sprint(iter.Key().Interface()))] = iter.Value().Interface()
+		}
+
+		return b.redactMap(key, in)
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return value
+		}
+
+		var out = make([]interface{}, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			out = append(out, b.redactValue(key, rv.Index(i).Interface()))
+		}
+
+		return out
+	default:
+		return value
+	}
+}
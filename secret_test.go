@@ -0,0 +1,57 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_Secret(t *testing.T) {
+	var dir = configDir(t, map[string]string{
+		"config.json": `{
+			"app": {"name": "test", "token": "t0ken"},
+			"db": {"host": "localhost", "password": "pa$$"},
+			"cache": {"addr": "redis", "auth": {"user": "u", "pass": "p"}}
+		}`,
+	})
+
+	var b = NewBundle(Secret("APP.Token", "db.*", "cache.auth"))
+
+	var _, err = provide(t, b, dir)
+	require.NoError(t, err)
+
+	for _, dump := range []func(buf *bytes.Buffer) error{
+		func(buf *bytes.Buffer) error { return b.DebugDump(buf) },
+		func(buf *bytes.Buffer) error { return b.Export(buf, "json") },
+	} {
+		var buf bytes.Buffer
+		require.NoError(t, dump(&buf))
+
+		var settings map[string]interface{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &settings))
+		assert.Equal(t, map[string]interface{}{
+			"app":   map[string]interface{}{"name": "test", "token": secretMask},
+			"db":    map[string]interface{}{"host": secretMask, "password": secretMask},
+			"cache": map[string]interface{}{"addr": "redis", "auth": secretMask},
+		}, settings)
+	}
+}
+
+func TestBundle_isSecret(t *testing.T) {
+	var b = NewBundle(Secret("db.*", "token"))
+
+	assert.True(t, b.isSecret("db.password"))
+	assert.True(t, b.isSecret("DB.Nested.Key"))
+	assert.False(t, b.isSecret("db"))
+	assert.True(t, b.isSecret("token"))
+	assert.True(t, b.isSecret("token.value"))
+	assert.False(t, b.isSecret("tokens"))
+	assert.True(t, NewBundle(Secret("*")).isSecret("any.key"))
+}
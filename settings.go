@@ -0,0 +1,270 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// parsers are parsers of config types unsupported by viper, they are registered by RegisterParser.
+var parsers = map[string]func(data []byte) (map[string]interface{}, error){}
+
+// RegisterParser registers parser of config type unsupported by viper, e.g. by the hcl2 module, so config files
+// of the type are searched and parsed by the bundle. It isn't safe for concurrent use and must be called on init.
+func RegisterParser(configType string, parser func(data []byte) (map[string]interface{}, error)) {
+	parsers[strings.ToLower(configType)] = parser
+}
+
+// readFile parses file to settings map.
+func (b *Bundle) readFile(path string) (map[string]interface{}, error) {
+	if b.isStreamed(path) {
+		return b.streamFile(path)
+	}
+
+	var data, err = b.readFileData(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.parse(path, data)
+}
+
+// readFileData reads file from bundle filesystem, it is OS filesystem unless fs.FS is provided.
+func (b *Bundle) readFileData(path string) ([]byte, error) {
+	if b.fsys != nil {
+		return fs.ReadFile(b.fsys, filepath.ToSlash(path))
+	}
+
+	return os.ReadFile(path)
+}
+
+// statFile returns file info from bundle filesystem.
+func (b *Bundle) statFile(path string) (fs.FileInfo, error) {
+	if b.fsys != nil {
+		return fs.Stat(b.fsys, filepath.ToSlash(path))
+	}
+
+	return os.Stat(path)
+}
+
+// checkConfigSize checks size of the config file to be read doesn't exceed max config size.
+func (b *Bundle) checkConfigSize() error {
+	var path = b.viper.ConfigFileUsed()
+	if len(path) == 0 {
+		path = b.findConfigFile()
+	}
+
+	if len(path) == 0 {
+		return nil
+	}
+
+	var info, err = b.statFile(path)
+	if err != nil {
+		// read reports missing or unreadable file
+		return nil
+	}
+
+	if info.Size() > b.maxConfigSize {
+		return fmt.Errorf("config file %q has %d bytes, limit is %d : %w", path, info.Size(), b.maxConfigSize, ErrConfigTooLarge)
+	}
+
+	return nil
+}
+
+// parse parses file data to settings map, gzip compressed data is decompressed.
+func (b *Bundle) parse(path string, data []byte) (_ map[string]interface{}, err error) {
+	if isCompressed(path) {
+		if data, err = decompress(data); err != nil {
+			return nil, fmt.Errorf("unable to decompress config file %q : %w", path, err)
+		}
+	}
+
+	if data, err = b.decodeCharset(data); err != nil {
+		return nil, err
+	}
+
+	var configType = b.fileType(path)
+	if parser, ok := parsers[configType]; ok {
+		return parser(data)
+	}
+
+	var v = viper.New()
+	v.SetConfigType(configType)
+
+	if err = v.ReadConfig(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+
+	return v.AllSettings(), nil
+}
+
+// fileType returns config type of file inferred from file extension ignoring compression suffix,
+// it falls back to bundle config type for unsupported extensions.
+func (b *Bundle) fileType(path string) string {
+	var ext = strings.TrimPrefix(filepath.Ext(strings.TrimSuffix(path, gzipExt)), ".")
+	if _, ok := parsers[ext]; ok {
+		return ext
+	}
+
+	if !isSupportedExt(ext) && len(b.configType) > 0 {
+		return b.configType
+	}
+
+	return ext
+}
+
+// isParsed reports whether config file is parsed by bundle instead of viper.
+func (b *Bundle) isParsed(path string) bool {
+	var _, ok = parsers[b.fileType(path)]
+	return ok || isCompressed(path) || b.isCharsetDecoded() || b.isStreamed(path)
+}
+
+// replaceConfig replaces config layer of viper instance with settings.
+func (b *Bundle) replaceConfig(settings map[string]interface{}) error {
+	if b.streamLarge {
+		return b.replaceConfigMap(settings)
+	}
+
+	var data, err = yaml.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("unable to encode config : %w", err)
+	}
+
+	var configType = b.configType
+	if len(configType) == 0 {
+		configType = b.fileType(b.viper.ConfigFileUsed())
+	}
+
+	b.viper.SetConfigType("yaml")
+	defer b.viper.SetConfigType(configType)
+
+	return b.viper.ReadConfig(bytes.NewReader(data))
+}
+
+// isSupportedExt reports whether viper supports file extension.
+func isSupportedExt(ext string) bool {
+	ext = strings.TrimPrefix(ext, ".")
+	for _, supported := range viper.SupportedExts {
+		if ext == supported {
+			return true
+		}
+	}
+
+	return false
+}
+
+// deepMerge merges src into dst recursively, src values take precedence.
+func deepMerge(dst, src map[string]interface{}) map[string]interface{} {
+	if dst == nil {
+		dst = make(map[string]interface{}, len(src))
+	}
+
+	for key, value := range src {
+		var srcMap, srcOk = value.(map[string]interface{})
+		if !srcOk {
+			dst[key] = value
+			continue
+		}
+
+		var dstMap, _ = dst[key].(map[string]interface{})
+		dst[key] = deepMerge(dstMap, srcMap)
+	}
+
+	return dst
+}
+
+// lowercaseKeys returns copy of value with all map keys lowercased recursively.
+func lowercaseKeys(settings map[string]interface{}) map[string]interface{} {
+	var result = make(map[string]interface{}, len(settings))
+	for key, value := range settings {
+		result[strings.ToLower(key)] = lowercaseValue(value)
+	}
+
+	return result
+}
+
+func lowercaseValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		return lowercaseKeys(typed)
+	case []interface{}:
+		var result = make([]interface{}, 0, len(typed))
+		for _, item := range typed {
+			result = append(result, lowercaseValue(item))
+		}
+
+		return result
+	default:
+		return value
+	}
+}
+
+// flatten returns settings as map of dotted keys to leaf values.
+func flatten(settings map[string]interface{}) map[string]interface{} {
+	var result = make(map[string]interface{}, len(settings))
+	flattenInto(result, "", settings)
+
+	return result
+}
+
+func flattenInto(result map[string]interface{}, prefix string, settings map[string]interface{}) {
+	for key, value := range settings {
+		if len(prefix) > 0 {
+			key = prefix + keyDelimiter + key
+		}
+
+		if child, ok := value.(map[string]interface{}); ok && len(child) > 0 {
+			flattenInto(result, key, child)
+			continue
+		}
+
+		result[key] = value
+	}
+}
+
+// setPath sets value of nested settings map by key path, creating intermediate maps.
+func setPath(settings map[string]interface{}, path []string, value interface{}) {
+	for _, key := range path[:len(path)-1] {
+		var child, ok = settings[key].(map[string]interface{})
+		if !ok {
+			child = make(map[string]interface{})
+			settings[key] = child
+		}
+
+		settings = child
+	}
+
+	settings[path[len(path)-1]] = value
+}
+
+// lookup returns value of nested settings map by key.
+func lookup(settings map[string]interface{}, key string) (interface{}, bool) {
+	var value interface{} = settings
+	for _, part := range strings.Split(strings.ToLower(key), keyDelimiter) {
+		var node, ok = value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		if value, ok = node[part]; !ok {
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+// copySettings returns deep copy of nested settings maps.
+func copySettings(settings map[string]interface{}) map[string]interface{} {
+	return deepMerge(nil, settings)
+}
@@ -0,0 +1,90 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLowercaseKeys(t *testing.T) {
+	assert.Equal(t, map[string]interface{}{
+		"server": map[string]interface{}{"host": "Localhost"},
+		"list":   []interface{}{map[string]interface{}{"name": "A"}, "B"},
+	}, lowercaseKeys(map[string]interface{}{
+		"Server": map[string]interface{}{"HOST": "Localhost"},
+		"List":   []interface{}{map[string]interface{}{"Name": "A"}, "B"},
+	}))
+}
+
+func TestRegisterParser(t *testing.T) {
+	RegisterParser("KV", func(data []byte) (map[string]interface{}, error) {
+		var settings = make(map[string]interface{})
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			var key, value, _ = strings.Cut(line, "=")
+			settings[key] = value
+		}
+
+		return settings, nil
+	})
+
+	t.Cleanup(func() {
+		delete(parsers, "kv")
+	})
+
+	var dir = configDir(t, map[string]string{"config.kv": "name=app\nport=8080\n"})
+
+	var v, err = provide(t, NewBundle(), dir)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "config.kv"), v.ConfigFileUsed(), "config file of registered type must be searched")
+	assert.Equal(t, "app", v.GetString("name"))
+	assert.Equal(t, 8080, v.GetInt("port"))
+}
+
+func TestBundle_NormalizeKeys(t *testing.T) {
+	var dir = configDir(t, map[string]string{
+		"config.json": `{"Server": {"Host": "Localhost"}, "Backends": [{"Name": "primary", "Weight": "2"}]}`,
+	})
+
+	var v, err = provide(t, NewBundle(NormalizeKeys()), dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Localhost", v.GetString("server.host"))
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "primary", "weight": "2"},
+	}, v.Get("backends"))
+	assert.Equal(t, map[string]interface{}{
+		"server":   map[string]interface{}{"host": "Localhost"},
+		"backends": []interface{}{map[string]interface{}{"name": "primary", "weight": "2"}},
+	}, v.AllSettings())
+}
+
+func TestBundle_MaxConfigSize(t *testing.T) {
+	var dir = configDir(t, map[string]string{
+		"config.json": `{"name": "application"}`,
+		"large.json":  `{"name": "application", "description": "too large"}`,
+	})
+
+	var v, err = provide(t, NewBundle(MaxConfigSize(32)), dir)
+	require.NoError(t, err)
+	assert.Equal(t, "application", v.GetString("name"))
+
+	_, err = provide(t, NewBundle(MaxConfigSize(16)), dir)
+	require.ErrorIs(t, err, ErrConfigTooLarge)
+	assert.Contains(t, err.Error(), "config.json")
+
+	var large = filepath.Join(dir, "large.json")
+	_, err = provide(t, NewBundle(MaxConfigSize(32)), dir, "--config", large)
+	require.ErrorIs(t, err, ErrConfigTooLarge)
+	assert.Contains(t, err.Error(), fmt.Sprintf("config file %q has 51 bytes, limit is 32", large))
+
+	_, err = provide(t, NewBundle(), dir, "--config", large)
+	require.NoError(t, err, "size must be unlimited by default")
+}
@@ -0,0 +1,42 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// CheckShape checks that all settings would unmarshal into the prototype struct without producing it,
+// the settings are decoded into a throwaway instance and keys unused by the prototype are reported.
+// The prototype is used only for its type and is never modified.
+func (b *Bundle) CheckShape(prototype interface{}) error {
+	var rt = reflect.TypeOf(prototype)
+	for rt != nil && rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+
+	if rt == nil || rt.Kind() != reflect.Struct {
+		return fmt.Errorf("unable to check config shape : %w", ErrInvalidPrototype)
+	}
+
+	defer b.rlock("")()
+
+	var config = b.decoderConfig(reflect.New(rt).Interface())
+	config.ErrorUnused = true
+
+	var decoder, err = mapstructure.NewDecoder(config)
+	if err == nil {
+		err = decoder.Decode(b.nsSettings(b.viper.AllSettings()))
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to check config shape of %s : %w", rt, err)
+	}
+
+	return nil
+}
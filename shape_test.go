@@ -0,0 +1,70 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// shapeConfig is prototype of shape check tests.
+type shapeConfig struct {
+	Name string `mapstructure:"name"`
+	DB   struct {
+		Port    int           `mapstructure:"port"`
+		Timeout time.Duration `mapstructure:"timeout"`
+	} `mapstructure:"db"`
+}
+
+func TestBundle_CheckShape(t *testing.T) {
+	var tests = []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{name: "valid", content: `{"name": "app", "db": {"port": "5432", "timeout": "5s"}}`},
+		{name: "type mismatch", content: `{"name": "app", "db": {"port": "abc"}}`, err: "cannot parse 'db.port' as int"},
+		{name: "unused key", content: `{"name": "app", "db": {"prot": 5432}}`, err: "invalid keys: prot"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				dir = configDir(t, map[string]string{"config.json": tt.content})
+				b   = NewBundle()
+			)
+
+			var _, err = provide(t, b, dir)
+			require.NoError(t, err)
+
+			var prototype = &shapeConfig{Name: "prototype"}
+
+			err = b.CheckShape(prototype)
+			assert.Equal(t, "prototype", prototype.Name, "prototype must not be modified")
+
+			if len(tt.err) == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			require.ErrorContains(t, err, "unable to check config shape of viper.shapeConfig")
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestBundle_CheckShapeInvalidPrototype(t *testing.T) {
+	var b = NewBundle()
+
+	var _, err = provide(t, b, configDir(t, map[string]string{"config.json": `{}`}))
+	require.NoError(t, err)
+
+	for _, prototype := range []interface{}{nil, 1, map[string]interface{}{}, new(string)} {
+		assert.ErrorIs(t, b.CheckShape(prototype), ErrInvalidPrototype)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// InConfig reports whether the key is provided by config file, unlike IsSet it ignores defaults,
+// env variables, flags and overrides.
+func (b *Bundle) InConfig(key string) bool {
+	defer b.rlock("")()
+
+	var _, ok = lookup(b.fileConfig, b.nsKey(key))
+	return ok
+}
+
+// FilePreferredKeys option makes config file values of keys take precedence over env variables and flags.
+// It inverts viper precedence for these keys only, the file values are set as overrides after every read.
+func FilePreferredKeys(keys ...string) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.filePreferred = append(bundle.filePreferred, keys...)
+	})
+}
+
+// applyFilePreferred overrides file preferred keys by config file values.
+func (b *Bundle) applyFilePreferred() {
+	for _, key := range b.filePreferred {
+		if value, ok := lookup(b.fileConfig, key); ok {
+			b.viper.Set(key, value)
+		}
+	}
+}
+
+// isFilePreferred reports whether config file value of the key is preferred.
+func (b *Bundle) isFilePreferred(key string) bool {
+	for _, preferred := range b.filePreferred {
+		if strings.EqualFold(preferred, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ConfigModTime returns modification time of the used config file.
+func (b *Bundle) ConfigModTime() (time.Time, error) {
+	defer b.rlock("")()
+
+	var path = b.viper.ConfigFileUsed()
+	if len(path) == 0 || !b.configFileFound {
+		return time.Time{}, fmt.Errorf("unable to get config modification time : %w", ErrNoConfigFile)
+	}
+
+	var info, err = b.statFile(path)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unable to get config modification time : %w", err)
+	}
+
+	if !info.Mode().IsRegular() {
+		return time.Time{}, fmt.Errorf("unable to get config modification time : '%s' is not a file", path)
+	}
+
+	return info.ModTime(), nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_InConfig(t *testing.T) {
+	t.Setenv("ENV_DB_USER", "env")
+
+	var dir = configDir(t, map[string]string{
+		"config.json": `{"db": {"host": "localhost", "port": 5432}}`,
+	})
+
+	var b = NewBundle(Default("db.port", 3306), Default("db.name", "app"), Default("db.user", "root"))
+
+	var _, err = provide(t, b, dir)
+	require.NoError(t, err)
+
+	assert.True(t, b.InConfig("db.host"))
+	assert.True(t, b.InConfig("DB.Port"))
+	assert.True(t, b.InConfig("db"))
+	assert.False(t, b.InConfig("db.name"), "defaulted key must not be in config")
+	assert.False(t, b.InConfig("db.user"), "env key must not be in config")
+	assert.False(t, b.InConfig("db.missing"))
+
+	require.NoError(t, b.Set("db.name", "override"))
+	assert.False(t, b.InConfig("db.name"), "overridden key must not be in config")
+}
+
+func TestBundle_FilePreferredKeys(t *testing.T) {
+	t.Setenv("ENV_DB_HOST", "env")
+	t.Setenv("ENV_DB_PORT", "6432")
+
+	var dir = configDir(t, map[string]string{
+		"config.json": `{"db": {"host": "file", "port": 5432}}`,
+	})
+
+	var b = NewBundle(FilePreferredKeys("db.host", "db.name"), OnChange(func(fsnotify.Event) {}))
+
+	var v, err = provide(t, b, dir)
+	require.NoError(t, err)
+	assert.Equal(t, "file", v.GetString("db.host"), "file must win for preferred key")
+	assert.Equal(t, 6432, v.GetInt("db.port"), "env must win for other keys")
+	assert.False(t, v.IsSet("db.name"))
+
+	replaceFile(t, v.ConfigFileUsed(), `{"db": {"host": "reloaded", "port": 5432}}`)
+	assert.Eventually(t, func() bool {
+		return b.View().GetString("db.host") == "reloaded"
+	}, 2*time.Second, 10*time.Millisecond, "file must win for preferred key after reload")
+}
+
+func TestBundle_ConfigModTime(t *testing.T) {
+	var (
+		dir = configDir(t, map[string]string{"config.json": `{}`})
+		b   = NewBundle()
+	)
+
+	var v, err = provide(t, b, dir)
+	require.NoError(t, err)
+
+	var modTime = time.Date(2020, time.January, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, os.Chtimes(v.ConfigFileUsed(), modTime, modTime))
+
+	info, err := os.Stat(v.ConfigFileUsed())
+	require.NoError(t, err)
+
+	actual, err := b.ConfigModTime()
+	require.NoError(t, err)
+	assert.True(t, info.ModTime().Equal(actual))
+	assert.True(t, modTime.Equal(actual))
+
+	b = NewBundle(DontUseConfigFile())
+	_, err = provide(t, b, dir)
+	require.NoError(t, err)
+
+	_, err = b.ConfigModTime()
+	assert.ErrorIs(t, err, ErrNoConfigFile)
+}
@@ -0,0 +1,181 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type (
+	// Source is config source of the sources chain.
+	Source interface {
+		// Read returns config data and its type, nil data means the source is unavailable and skipped.
+		Read() ([]byte, string, error)
+	}
+
+	// fileSource reads config file, a missing file is skipped.
+	fileSource struct {
+		path string
+	}
+
+	// embedSource reads config file from file system.
+	embedSource struct {
+		fsys fs.FS
+		name string
+	}
+
+	// envJSONSource reads JSON config from env variable.
+	envJSONSource struct {
+		name   string
+		bundle *Bundle
+	}
+
+	// flagSource reads config file which path is value of the bundle flag.
+	flagSource struct {
+		name   string
+		bundle *Bundle
+	}
+
+	// bundleSource is source bound to the bundle.
+	bundleSource interface {
+		bind(bundle *Bundle)
+	}
+)
+
+// Sources option merges config sources in declared order on top of config file, later sources take
+// precedence over earlier ones. The config file becomes optional, unavailable sources are skipped.
+func Sources(sources ...Source) Option {
+	return optionFunc(func(bundle *Bundle) {
+		for _, source := range sources {
+			if bs, ok := source.(bundleSource); ok {
+				bs.bind(bundle)
+			}
+		}
+
+		bundle.sources = append(bundle.sources, sources...)
+	})
+}
+
+// FileSource returns source reading config file, the config type is inferred from file extension.
+// A missing file is skipped.
+func FileSource(path string) Source {
+	return &fileSource{path: path}
+}
+
+// EmbedSource returns source reading named config file from file system, for example embed.FS.
+func EmbedSource(fsys fs.FS, name string) Source {
+	return &embedSource{fsys: fsys, name: name}
+}
+
+// EnvJSONSource returns source reading JSON config from env variable, an unset variable is skipped.
+func EnvJSONSource(name string) Source {
+	return &envJSONSource{name: name}
+}
+
+// FlagSource returns source reading config file which path is value of the named bundle flag,
+// for example "config". An empty flag value is skipped.
+func FlagSource(name string) Source {
+	return &flagSource{name: name}
+}
+
+// Read implements the Source interface.
+func (s *fileSource) Read() ([]byte, string, error) {
+	var data, err = os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, "", nil
+	}
+
+	return data, sourceType(s.path), err
+}
+
+// Read implements the Source interface.
+func (s *embedSource) Read() ([]byte, string, error) {
+	var data, err = fs.ReadFile(s.fsys, s.name)
+	return data, sourceType(s.name), err
+}
+
+// Read implements the Source interface.
+func (s *envJSONSource) Read() ([]byte, string, error) {
+	var value, ok = s.bundle.lookupEnv(s.name)
+	if !ok {
+		return nil, "", nil
+	}
+
+	return []byte(value), "json", nil
+}
+
+func (s *envJSONSource) bind(bundle *Bundle) {
+	s.bundle = bundle
+}
+
+// Read implements the Source interface.
+func (s *flagSource) Read() ([]byte, string, error) {
+	if s.bundle.flagSet == nil || s.bundle.flagSet.Lookup(s.name) == nil {
+		return nil, "", nil
+	}
+
+	var path = s.bundle.flagSet.Lookup(s.name).Value.String()
+	if len(path) == 0 {
+		return nil, "", nil
+	}
+
+	var data, err = os.ReadFile(path)
+	return data, sourceType(path), err
+}
+
+func (s *flagSource) bind(bundle *Bundle) {
+	s.bundle = bundle
+}
+
+// sourceName returns name of source reported on merges.
+func sourceName(source Source, i int) string {
+	switch s := source.(type) {
+	case *fileSource:
+		return s.path
+	case *embedSource:
+		return s.name
+	case *envJSONSource:
+		return "env:" + s.name
+	case *flagSource:
+		return "flag:" + s.name
+	}
+
+	return fmt.Sprintf("source #%d", i+1)
+}
+
+// sourceType returns config type inferred from file extension.
+func sourceType(path string) string {
+	return strings.TrimPrefix(filepath.Ext(strings.TrimSuffix(path, gzipExt)), ".")
+}
+
+// readSources reads and merges sources in order.
+func (b *Bundle) readSources() (map[string]interface{}, error) {
+	var settings = make(map[string]interface{})
+	for i, source := range b.sources {
+		var data, typ, err = source.Read()
+		if err != nil {
+			return nil, fmt.Errorf("unable to read config source #%d : %w", i+1, err)
+		}
+
+		if data == nil {
+			continue
+		}
+
+		var content map[string]interface{}
+		if content, err = b.parse("source."+typ, data); err != nil {
+			return nil, fmt.Errorf("unable to parse config source #%d : %w", i+1, err)
+		}
+
+		b.addMergeLayer(layerSource, sourceName(source, i), content)
+		settings = deepMerge(settings, content)
+	}
+
+	return settings, nil
+}
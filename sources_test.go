@@ -0,0 +1,85 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sourceFunc is Source implemented by function.
+type sourceFunc func() ([]byte, string, error)
+
+// Read implements the Source interface.
+func (fn sourceFunc) Read() ([]byte, string, error) {
+	return fn()
+}
+
+func TestBundle_Sources(t *testing.T) {
+	var (
+		fsys = fstest.MapFS{
+			"defaults.yaml": {Data: []byte("level: embed\nembed: true\n")},
+		}
+		etc  = configDir(t, map[string]string{"app.toml": "level = \"etc\"\netc = true\n"})
+		home = configDir(t, map[string]string{"app.json": `{"level": "home", "home": true}`})
+	)
+
+	var b = NewBundle(Sources(
+		EmbedSource(fsys, "defaults.yaml"),
+		FileSource(filepath.Join(etc, "app.toml")),
+		FileSource(filepath.Join(home, "missing.json")),
+		FileSource(filepath.Join(home, "app.json")),
+	))
+
+	var v, err = provide(t, b, t.TempDir())
+	require.NoError(t, err, "config file must be optional")
+	assert.Equal(t, "home", v.GetString("level"), "later source must win")
+	assert.True(t, v.GetBool("embed"))
+	assert.True(t, v.GetBool("etc"))
+	assert.True(t, v.GetBool("home"))
+}
+
+func TestBundle_SourcesEnvAndFlag(t *testing.T) {
+	t.Setenv("APP_CONFIG", `{"level": "env", "env": true}`)
+
+	var (
+		dir  = configDir(t, map[string]string{"config.json": `{"level": "file"}`})
+		flag = configDir(t, map[string]string{"flag.json": `{"level": "flag", "flag": true}`})
+		b    = NewBundle(ConfigPath(dir), Sources(EnvJSONSource("APP_CONFIG"), FlagSource("config")))
+	)
+
+	var v, err = provide(t, b, t.TempDir())
+	require.NoError(t, err)
+	assert.Equal(t, "env", v.GetString("level"), "sources must be merged on top of config file")
+	assert.True(t, v.GetBool("env"))
+
+	v, err = provide(t, NewBundle(Sources(EnvJSONSource("APP_CONFIG"), FlagSource("config"))), t.TempDir(),
+		"--config", filepath.Join(flag, "flag.json"))
+
+	require.NoError(t, err)
+	assert.Equal(t, "flag", v.GetString("level"))
+	assert.True(t, v.GetBool("env"))
+	assert.True(t, v.GetBool("flag"))
+}
+
+func TestBundle_SourcesError(t *testing.T) {
+	var b = NewBundle(Sources(
+		sourceFunc(func() ([]byte, string, error) { return nil, "", nil }),
+		sourceFunc(func() ([]byte, string, error) { return nil, "", errors.New("unavailable") }),
+	))
+
+	var _, err = provide(t, b, t.TempDir())
+	require.ErrorContains(t, err, "unable to read config source #2 : unavailable")
+
+	b = NewBundle(Sources(sourceFunc(func() ([]byte, string, error) { return []byte("{"), "json", nil })))
+
+	_, err = provide(t, b, t.TempDir())
+	require.ErrorContains(t, err, "unable to parse config source #1")
+}
@@ -0,0 +1,48 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// readSplitKeyDir reads directory of single value files to settings map.
+func readSplitKeyDir(dir string) (map[string]interface{}, error) {
+	var entries, err = os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read split key dir '%s' : %w", dir, err)
+	}
+
+	var settings = make(map[string]interface{}, len(entries))
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		var path = filepath.Join(dir, entry.Name())
+
+		var info os.FileInfo
+		if info, err = os.Stat(path); err != nil {
+			return nil, fmt.Errorf("unable to stat split key file '%s' : %w", path, err)
+		}
+
+		if !info.Mode().IsRegular() {
+			continue
+		}
+
+		var data []byte
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("unable to read split key file '%s' : %w", path, err)
+		}
+
+		var key = strings.ToLower(strings.ReplaceAll(entry.Name(), "__", keyDelimiter))
+		setPath(settings, strings.Split(key, keyDelimiter), strings.TrimRight(string(data), "\r\n"))
+	}
+
+	return settings, nil
+}
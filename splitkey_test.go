@@ -0,0 +1,52 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadSplitKeyDir(t *testing.T) {
+	var dir = configDir(t, map[string]string{
+		"db.host":          "localhost\n",
+		"db__port":         "5432",
+		"Name":             "app",
+		".hidden":          "skipped",
+		"..data/db.user":   "skipped",
+		"nested/db.passwd": "skipped",
+	})
+
+	var settings, err = readSplitKeyDir(dir)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"db":   map[string]interface{}{"host": "localhost", "port": "5432"},
+		"name": "app",
+	}, settings)
+
+	_, err = readSplitKeyDir(filepath.Join(dir, "missing"))
+	require.Error(t, err)
+}
+
+func TestBundle_SplitKeyDir(t *testing.T) {
+	var (
+		dir = configDir(t, map[string]string{
+			"config.json": `{"db": {"host": "file", "name": "app"}}`,
+		})
+		keys = configDir(t, map[string]string{
+			"db.host": "configmap",
+			"db.port": "5432",
+		})
+	)
+
+	var v, err = provide(t, NewBundle(SplitKeyDir(keys)), dir)
+	require.NoError(t, err)
+	assert.Equal(t, "configmap", v.GetString("db.host"))
+	assert.Equal(t, 5432, v.GetInt("db.port"))
+	assert.Equal(t, "app", v.GetString("db.name"))
+}
@@ -0,0 +1,121 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// StreamLargeConfig option decodes JSON config files token by token from the file stream and passes
+// the settings to viper without re-encoding them, so the raw file content isn't buffered in memory.
+// It helps JSON files only, other formats and compressed or charset decoded files are buffered.
+func StreamLargeConfig() Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.streamLarge = true
+	})
+}
+
+// isStreamed reports whether config file is streamed.
+func (b *Bundle) isStreamed(path string) bool {
+	return b.streamLarge && b.fileType(path) == "json" && !isCompressed(path) && !b.isCharsetDecoded()
+}
+
+// streamFile decodes JSON config file from the file stream.
+func (b *Bundle) streamFile(path string) (_ map[string]interface{}, err error) {
+	var file io.ReadCloser
+	if b.fsys != nil {
+		file, err = b.fsys.Open(filepath.ToSlash(path))
+	} else {
+		file, err = os.Open(path)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	var value interface{}
+	if value, err = decodeStream(json.NewDecoder(file)); err != nil {
+		return nil, fmt.Errorf("unable to decode config file %q : %w", path, err)
+	}
+
+	var settings, ok = value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unable to decode config file %q : config is not an object", path)
+	}
+
+	return settings, nil
+}
+
+// decodeStream decodes JSON value token by token, so the decoder doesn't buffer the whole value.
+// Object keys are lowercased.
+func decodeStream(decoder *json.Decoder) (interface{}, error) {
+	var token, err = decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		var object = make(map[string]interface{})
+		for decoder.More() {
+			if token, err = decoder.Token(); err != nil {
+				return nil, err
+			}
+
+			var key, _ = token.(string)
+
+			var value interface{}
+			if value, err = decodeStream(decoder); err != nil {
+				return nil, err
+			}
+
+			object[strings.ToLower(key)] = value
+		}
+
+		_, err = decoder.Token()
+
+		return object, err
+	case json.Delim('['):
+		var array = make([]interface{}, 0)
+		for decoder.More() {
+			var value interface{}
+			if value, err = decodeStream(decoder); err != nil {
+				return nil, err
+			}
+
+			array = append(array, value)
+		}
+
+		_, err = decoder.Token()
+
+		return array, err
+	default:
+		return token, nil
+	}
+}
+
+// replaceConfigMap replaces config layer of viper instance with settings without encoding them.
+func (b *Bundle) replaceConfigMap(settings map[string]interface{}) error {
+	var configType = b.configType
+	if len(configType) == 0 {
+		configType = b.fileType(b.viper.ConfigFileUsed())
+	}
+
+	b.viper.SetConfigType("json")
+	defer b.viper.SetConfigType(configType)
+
+	if err := b.viper.ReadConfig(strings.NewReader("{}")); err != nil {
+		return err
+	}
+
+	return b.viper.MergeConfigMap(settings)
+}
@@ -0,0 +1,61 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_StreamLargeConfig(t *testing.T) {
+	var content strings.Builder
+	content.WriteString(`{"Name": "app", "nested": {"list": [1, "two", true, null, {"Key": "value"}]}, "items": {`)
+	for i := 0; i < 5000; i++ {
+		if i > 0 {
+			content.WriteString(",")
+		}
+
+		_, _ = fmt.Fprintf(&content, `"item%d": {"id": %d, "name": "item %d"}`, i, i, i)
+	}
+	content.WriteString("}}")
+
+	var dir = configDir(t, map[string]string{"config.json": content.String()})
+
+	var streamed, err = provide(t, NewBundle(StreamLargeConfig()), dir)
+	require.NoError(t, err)
+	assert.Equal(t, "app", streamed.GetString("name"))
+	assert.Equal(t, 4999, streamed.GetInt("items.item4999.id"))
+	assert.Equal(t, "item 42", streamed.GetString("items.item42.name"))
+	assert.Len(t, streamed.GetStringMap("items"), 5000)
+
+	buffered, err := provide(t, NewBundle(), dir)
+	require.NoError(t, err)
+	assert.Equal(t, buffered.AllSettings(), streamed.AllSettings(), "streamed config must match buffered one")
+}
+
+func TestBundle_StreamLargeConfigInvalid(t *testing.T) {
+	var tests = []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{name: "not object", content: `[1, 2]`, err: "config is not an object"},
+		{name: "malformed", content: `{"a": }`, err: "unable to decode config file"},
+		{name: "truncated", content: `{"a": {"b": 1}`, err: "unable to decode config file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var dir = configDir(t, map[string]string{"config.json": tt.content})
+
+			var _, err = provide(t, NewBundle(StreamLargeConfig()), dir)
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"reflect"
+	"strings"
+)
+
+// defaultTagName is struct tag name of default values.
+const defaultTagName = "default"
+
+// DefaultsFromStruct option sets default values for keys from `default` tags of struct fields.
+// Keys are derived from the field path using mapstructure (or configured by TagName option) names,
+// nested structs are supported. Tag values are decoded to the field type.
+func DefaultsFromStruct(v interface{}) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.defaultsFromStruct("", reflect.TypeOf(v))
+	})
+}
+
+func (b *Bundle) defaultsFromStruct(prefix string, typ reflect.Type) {
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		var field = typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		var name, squash, skip = b.fieldKey(field)
+		if skip {
+			continue
+		}
+
+		var key = prefix
+		if !squash {
+			key = joinKey(prefix, name)
+		}
+
+		var value, ok = field.Tag.Lookup(defaultTagName)
+		if !ok {
+			b.defaultsFromStruct(key, field.Type)
+			continue
+		}
+
+		var typed = reflect.New(field.Type)
+		if err := b.decode(value, typed.Interface()); err != nil {
+			b.setDefault(key, value)
+			continue
+		}
+
+		b.setDefault(key, typed.Elem().Interface())
+	}
+}
+
+// fieldKey returns config key name of struct field according to the decoder tag name.
+func (b *Bundle) fieldKey(field reflect.StructField) (name string, squash bool, skip bool) {
+	var tagName = b.tagName
+	if len(tagName) == 0 {
+		tagName = "mapstructure"
+	}
+
+	var parts = strings.Split(field.Tag.Get(tagName), ",")
+	if parts[0] == "-" {
+		return "", false, true
+	}
+
+	for _, part := range parts[1:] {
+		if part == "squash" {
+			squash = true
+		}
+	}
+
+	name = parts[0]
+	if len(name) == 0 {
+		name = field.Name
+	}
+
+	return strings.ToLower(name), squash, false
+}
+
+// setDefault sets default value of key and remembers it.
+func (b *Bundle) setDefault(key string, value interface{}) {
+	b.defaults[strings.ToLower(key)] = value
+	b.viper.SetDefault(key, value)
+}
+
+// joinKey joins key parts with key delimiter.
+func joinKey(prefix string, key string) string {
+	if len(prefix) == 0 {
+		return key
+	}
+
+	return prefix + keyDelimiter + key
+}
@@ -0,0 +1,60 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultsFromStruct(t *testing.T) {
+	type (
+		Common struct {
+			Debug bool `mapstructure:"debug" default:"true"`
+		}
+
+		Config struct {
+			Common `mapstructure:",squash"`
+
+			Name string `mapstructure:"app_name" default:"app"`
+			DB   struct {
+				Host    string        `default:"localhost"`
+				Port    int           `default:"5432"`
+				Timeout time.Duration `default:"3s"`
+			} `mapstructure:"db"`
+			Hosts   []string `default:"a,b"`
+			Ignored string   `mapstructure:"-" default:"ignored"`
+			NoTag   string
+			private string `default:"private"`
+		}
+	)
+
+	var v, err = provide(t, NewBundle(DefaultsFromStruct(&Config{})), configDir(t, map[string]string{
+		"config.json": `{"db": {"port": 6432}}`,
+	}))
+
+	require.NoError(t, err)
+	assert.True(t, v.GetBool("debug"))
+	assert.Equal(t, "app", v.GetString("app_name"))
+	assert.Equal(t, "localhost", v.GetString("db.host"))
+	assert.Equal(t, 6432, v.GetInt("db.port"), "config file must override default")
+	assert.Equal(t, 3*time.Second, v.GetDuration("db.timeout"))
+	assert.Equal(t, []string{"a", "b"}, v.GetStringSlice("hosts"))
+	assert.False(t, v.IsSet("ignored"))
+	assert.False(t, v.IsSet("notag"))
+	assert.False(t, v.IsSet("private"))
+}
+
+func TestDefaultsFromStruct_TagName(t *testing.T) {
+	type Config struct {
+		Port int `cfg:"http_port" default:"8080"`
+	}
+
+	var b = NewBundle(TagName("cfg"), DefaultsFromStruct(Config{}))
+	assert.Equal(t, 8080, b.viper.GetInt("http_port"))
+}
@@ -0,0 +1,64 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// subFile is config file merged under key.
+type subFile struct {
+	key  string
+	path string
+}
+
+// SubFile option reads the file and merges its root under the key on top of config file, e.g. feature
+// flags file under "flags" key. If config file watching is enabled, the file is watched as well and
+// its change reloads config and calls change handlers.
+func SubFile(key, path string) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.subFiles = append(bundle.subFiles, subFile{key: strings.ToLower(key), path: path})
+	})
+}
+
+// readSubFiles reads and merges sub files under their keys in order.
+func (b *Bundle) readSubFiles() (map[string]interface{}, error) {
+	var settings = make(map[string]interface{})
+	for _, sf := range b.subFiles {
+		var content, err = b.readFile(sf.path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read sub file '%s' : %w", sf.path, err)
+		}
+
+		var nested = make(map[string]interface{})
+		setPath(nested, strings.Split(sf.key, keyDelimiter), content)
+
+		b.addMergeLayer(layerSubFile, sf.path, nested)
+		settings = deepMerge(settings, nested)
+	}
+
+	return settings, nil
+}
+
+// watchSubFiles watches sub files and reloads config on their change, watching stops with config file watching.
+func (b *Bundle) watchSubFiles() {
+	for _, sf := range b.subFiles {
+		var path = sf.path
+		var stop, err = b.WatchFile(path, func() {
+			b.handleChange(fsnotify.Event{Name: path, Op: fsnotify.Write}, nil)
+		})
+
+		if err != nil {
+			log.Printf("error watching sub file '%s': %v\n", path, err)
+			continue
+		}
+
+		b.watchStops = append(b.watchStops, stop)
+	}
+}
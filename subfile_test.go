@@ -0,0 +1,64 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_SubFile(t *testing.T) {
+	var dir = configDir(t, map[string]string{
+		"config.json": `{"name": "app", "flags": {"x": false, "y": true}}`,
+		"flags.yaml":  "x: true\nnested:\n  z: 1\n",
+	})
+
+	var v, err = provide(t, NewBundle(SubFile("features.Flags", filepath.Join(dir, "flags.yaml"))), dir)
+	require.NoError(t, err)
+	assert.True(t, v.GetBool("features.flags.x"))
+	assert.Equal(t, 1, v.GetInt("features.flags.nested.z"))
+
+	v, err = provide(t, NewBundle(SubFile("flags", filepath.Join(dir, "flags.yaml"))), dir)
+	require.NoError(t, err)
+	assert.True(t, v.GetBool("flags.x"), "sub file must be merged on top of config file")
+	assert.True(t, v.GetBool("flags.y"))
+	assert.Equal(t, "app", v.GetString("name"))
+
+	_, err = provide(t, NewBundle(SubFile("flags", filepath.Join(dir, "missing.yaml"))), dir)
+	assert.ErrorContains(t, err, "unable to read sub file")
+}
+
+func TestBundle_SubFileReload(t *testing.T) {
+	var (
+		dir = configDir(t, map[string]string{
+			"config.json": `{"name": "app"}`,
+			"flags.json":  `{"x": false}`,
+		})
+		file    = filepath.Join(dir, "flags.json")
+		changes = make(chan fsnotify.Event, 10)
+		b       = NewBundle(SubFile("flags", file), OnChange(func(in fsnotify.Event) { changes <- in }))
+	)
+
+	var v, err = provide(t, b, dir)
+	require.NoError(t, err)
+	assert.False(t, v.GetBool("flags.x"))
+
+	replaceFile(t, file, `{"x": true}`)
+
+	select {
+	case in := <-changes:
+		assert.Equal(t, file, in.Name)
+	case <-time.After(2 * time.Second):
+		require.Fail(t, "sub file change must call change handlers")
+	}
+
+	assert.True(t, b.View().GetBool("flags.x"))
+	assert.Equal(t, "app", b.View().GetString("name"))
+}
@@ -0,0 +1,131 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// maxTemplatePasses is maximum number of rendering passes resolving chained references.
+const maxTemplatePasses = 16
+
+// TemplateValues option renders string values of config as Go templates after the config is loaded,
+// all settings are passed as template data, e.g. "{{ .scheme }}://{{ .host }}". Rendering is repeated
+// until values stop changing, so values may reference other templated values.
+func TemplateValues() Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.templateValues = true
+	})
+}
+
+// renderTemplates renders templated values of the config layer up to the fixed point.
+func (b *Bundle) renderTemplates() (err error) {
+	for pass := 0; pass < maxTemplatePasses; pass++ {
+		var (
+			data    = b.viper.AllSettings()
+			changed bool
+		)
+
+		if changed, err = renderMap(b.config, data); err != nil {
+			return err
+		}
+
+		if !changed {
+			if key, ok := findTemplate(b.config, ""); ok {
+				return fmt.Errorf("key '%s' : unresolved template reference", key)
+			}
+
+			return nil
+		}
+
+		if err = b.replaceConfig(b.config); err != nil {
+			return err
+		}
+	}
+
+	return errors.New("unresolved template references, values reference each other")
+}
+
+// findTemplate returns the first key which value still contains a template after rendering.
+func findTemplate(value interface{}, key string) (string, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for name, item := range v {
+			if found, ok := findTemplate(item, joinKey(key, name)); ok {
+				return found, true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if _, ok := findTemplate(item, key); ok {
+				return key, true
+			}
+		}
+	case string:
+		return key, strings.Contains(v, "{{")
+	}
+
+	return "", false
+}
+
+// renderMap renders templated values of settings in place and reports whether any value was changed.
+func renderMap(settings map[string]interface{}, data map[string]interface{}) (changed bool, err error) {
+	for key, value := range settings {
+		var ok bool
+		if settings[key], ok, err = renderValue(value, data); err != nil {
+			return false, fmt.Errorf("key '%s' : %w", key, err)
+		}
+
+		changed = changed || ok
+	}
+
+	return changed, nil
+}
+
+// renderValue renders templated value and reports whether it was changed.
+func renderValue(value interface{}, data map[string]interface{}) (interface{}, bool, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		var changed, err = renderMap(v, data)
+		return v, changed, err
+	case []interface{}:
+		var changed bool
+		for i, item := range v {
+			var (
+				ok  bool
+				err error
+			)
+
+			if v[i], ok, err = renderValue(item, data); err != nil {
+				return nil, false, err
+			}
+
+			changed = changed || ok
+		}
+
+		return v, changed, nil
+	case string:
+		if !strings.Contains(v, "{{") {
+			return v, false, nil
+		}
+
+		var tpl, err = template.New("value").Option("missingkey=error").Parse(v)
+		if err != nil {
+			return nil, false, err
+		}
+
+		var sb strings.Builder
+		if err = tpl.Execute(&sb, data); err != nil {
+			return nil, false, err
+		}
+
+		return sb.String(), sb.String() != v, nil
+	default:
+		return value, false, nil
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_TemplateValues(t *testing.T) {
+	var dir = configDir(t, map[string]string{
+		"config.json": `{
+			"scheme": "https",
+			"host": "example.com",
+			"url": "{{ .scheme }}://{{ .host }}",
+			"api": {"url": "{{ .url }}/api", "hosts": ["{{ .host }}", "backup"]}
+		}`,
+	})
+
+	var v, err = provide(t, NewBundle(TemplateValues()), dir)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com", v.GetString("url"))
+	assert.Equal(t, "https://example.com/api", v.GetString("api.url"))
+	assert.Equal(t, []string{"example.com", "backup"}, v.GetStringSlice("api.hosts"))
+}
+
+func TestBundle_TemplateValuesUnresolved(t *testing.T) {
+	var dir = configDir(t, map[string]string{
+		"config.json": `{"url": "{{ .scheme }}://localhost"}`,
+	})
+
+	var _, err = provide(t, NewBundle(TemplateValues()), dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "key 'url'")
+	assert.Contains(t, err.Error(), "scheme")
+}
+
+func TestBundle_TemplateValuesCycle(t *testing.T) {
+	var dir = configDir(t, map[string]string{
+		"config.json": `{"a": "{{ .b }}+", "b": "{{ .a }}+"}`,
+	})
+
+	var _, err = provide(t, NewBundle(TemplateValues()), dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unresolved template references")
+}
@@ -0,0 +1,134 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"sort"
+	"strings"
+)
+
+// Sources of config values reported by ConfigNode.
+const (
+	// SourceFlag is source of values provided by changed bound flags.
+	SourceFlag = "flag"
+
+	// SourceEnv is source of values provided by env variables.
+	SourceEnv = "env"
+
+	// SourceFile is source of values provided by config file.
+	SourceFile = "file"
+
+	// SourceRemote is source of values provided by remote providers.
+	SourceRemote = "remote"
+
+	// SourceConfig is source of values merged into config by other options, like embedded config,
+	// split key directories and JSON env variables.
+	SourceConfig = "config"
+
+	// SourceDefault is source of default values.
+	SourceDefault = "default"
+
+	// SourceOverride is source of values set at runtime, it is reported for keys missing in other sources only.
+	SourceOverride = "override"
+)
+
+// ConfigNode is node of effective config tree.
+type ConfigNode struct {
+	// Key is full key of node, it is empty for the root node.
+	Key string `json:"key"`
+
+	// Value is value of leaf node, secret values are redacted.
+	Value interface{} `json:"value,omitempty"`
+
+	// Children are child nodes sorted by key.
+	Children []*ConfigNode `json:"children,omitempty"`
+
+	// Source is source of leaf node value.
+	Source string `json:"source,omitempty"`
+}
+
+// Tree returns effective config as a tree annotated with value sources, secret values are redacted.
+func (b *Bundle) Tree() *ConfigNode {
+	defer b.rlock("")()
+
+	return b.treeNode("", b.redact(b.viper.AllSettings()))
+}
+
+// treeNode returns tree node of the key value.
+func (b *Bundle) treeNode(key string, value interface{}) *ConfigNode {
+	var node = &ConfigNode{Key: key}
+
+	var settings, ok = value.(map[string]interface{})
+	if !ok {
+		node.Value = value
+		node.Source = b.source(key)
+
+		return node
+	}
+
+	var names = make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		node.Children = append(node.Children, b.treeNode(joinKey(key, name), settings[name]))
+	}
+
+	return node
+}
+
+// source returns source of the key value according to viper precedence.
+func (b *Bundle) source(key string) string {
+	if b.flagChanged(key) {
+		return SourceFlag
+	}
+
+	if name, ok := b.envBindings[key]; ok {
+		if value, ok := b.lookupEnv(name); ok && len(value) > 0 {
+			return SourceEnv
+		}
+	}
+
+	for _, alias := range b.envAliases {
+		if alias.key != key {
+			continue
+		}
+
+		for _, name := range alias.names {
+			if value, ok := b.lookupEnv(name); ok && len(value) > 0 {
+				return SourceEnv
+			}
+		}
+	}
+
+	if b.automaticEnv {
+		if value, ok := b.lookupEnv(b.envName(key)); ok && len(value) > 0 {
+			return SourceEnv
+		}
+	}
+
+	if _, ok := lookup(b.fileConfig, key); ok {
+		return SourceFile
+	}
+
+	if _, ok := lookup(b.remoteConfig, key); ok {
+		return SourceRemote
+	}
+
+	if _, ok := lookup(b.config, key); ok {
+		return SourceConfig
+	}
+
+	for name := range b.defaults {
+		if key == name || strings.HasPrefix(key, name+keyDelimiter) {
+			return SourceDefault
+		}
+	}
+
+	return SourceOverride
+}
@@ -0,0 +1,46 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_Tree(t *testing.T) {
+	t.Setenv("ENV_DB_PORT", "6432")
+
+	var dir = configDir(t, map[string]string{
+		"config.json": `{"db": {"host": "localhost", "port": 5432, "password": "secret"}, "name": "app"}`,
+	})
+
+	var b = NewBundle(Default("db.pool.size", 10), Secret("db.password"))
+
+	var _, err = provide(t, b, dir)
+	require.NoError(t, err)
+	require.NoError(t, b.Set("debug", true))
+
+	var root = b.Tree()
+	require.Empty(t, root.Key)
+	require.Nil(t, root.Value)
+	require.Len(t, root.Children, 3)
+
+	var db, debug, name = root.Children[0], root.Children[1], root.Children[2]
+	require.Equal(t, &ConfigNode{Key: "debug", Value: true, Source: SourceOverride}, debug)
+	require.Equal(t, &ConfigNode{Key: "name", Value: "app", Source: SourceFile}, name)
+
+	require.Equal(t, "db", db.Key)
+	require.Empty(t, db.Source)
+	require.Len(t, db.Children, 4)
+
+	var host, password, pool, port = db.Children[0], db.Children[1], db.Children[2], db.Children[3]
+	require.Equal(t, &ConfigNode{Key: "db.host", Value: "localhost", Source: SourceFile}, host)
+	require.Equal(t, &ConfigNode{Key: "db.password", Value: secretMask, Source: SourceFile}, password)
+	require.Equal(t, &ConfigNode{Key: "db.port", Value: "6432", Source: SourceEnv}, port)
+
+	require.Equal(t, "db.pool", pool.Key)
+	require.Equal(t, []*ConfigNode{{Key: "db.pool.size", Value: 10, Source: SourceDefault}}, pool.Children)
+}
@@ -0,0 +1,75 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"log"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// WarnUnusedKeys option logs config file keys which don't map to any field of the target struct
+// after config is loaded, they are likely typos or stale config. Unlike strict unmarshal it doesn't
+// fail viper provide.
+func WarnUnusedKeys(target interface{}) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.unusedTargets = append(bundle.unusedTargets, target)
+	})
+}
+
+// UnusedKeys returns sorted config file keys which don't map to any field of the target struct.
+func (b *Bundle) UnusedKeys(target interface{}) []string {
+	defer b.rlock("")()
+
+	return b.unusedKeys(target)
+}
+
+// unusedKeys returns sorted config file keys which don't map to any field of the target struct.
+func (b *Bundle) unusedKeys(target interface{}) []string {
+	var rt = reflect.TypeOf(target)
+	for rt != nil && rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+
+	if rt == nil {
+		return nil
+	}
+
+	var (
+		metadata mapstructure.Metadata
+		config   = b.decoderConfig(reflect.New(rt).Interface())
+	)
+
+	config.Metadata = &metadata
+
+	var decoder, err = mapstructure.NewDecoder(config)
+	if err != nil {
+		return nil
+	}
+
+	// decode errors are ignored, only key usage matters
+	_ = decoder.Decode(b.nsSettings(b.fileConfig))
+
+	var keys = make([]string, 0, len(metadata.Unused))
+	for _, key := range metadata.Unused {
+		keys = append(keys, strings.ToLower(key))
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// warnUnusedKeys logs unused config file keys of targets.
+func (b *Bundle) warnUnusedKeys() {
+	for _, target := range b.unusedTargets {
+		if keys := b.unusedKeys(target); len(keys) > 0 {
+			log.Printf("config file keys [%s] are unused by %T\n", strings.Join(keys, ", "), target)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_WarnUnusedKeys(t *testing.T) {
+	type Config struct {
+		DB struct {
+			Host string `mapstructure:"host"`
+			Port int    `mapstructure:"port"`
+		} `mapstructure:"db"`
+		Name string `mapstructure:"name"`
+	}
+
+	var (
+		buf    bytes.Buffer
+		output = log.Writer()
+	)
+
+	log.SetOutput(&buf)
+	t.Cleanup(func() {
+		log.SetOutput(output)
+	})
+
+	var dir = configDir(t, map[string]string{
+		"config.json": `{"db": {"host": "localhost", "prot": 5432}, "name": "app", "stale": true}`,
+	})
+
+	var b = NewBundle(WarnUnusedKeys(&Config{}), Default("extra", 1))
+
+	var _, err = provide(t, b, dir)
+	require.NoError(t, err, "unused keys must not fail provide")
+
+	assert.Equal(t, []string{"db.prot", "stale"}, b.UnusedKeys(Config{}))
+	assert.Contains(t, buf.String(), "config file keys [db.prot, stale] are unused by *viper.Config")
+}
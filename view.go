@@ -0,0 +1,350 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type (
+	// Config is read-only config accessor, it is provided by bundle and satisfied by both View and viper.Viper,
+	// so consumers don't depend on the concrete viper instance.
+	Config interface {
+		Get(key string) interface{}
+		GetBool(key string) bool
+		GetDuration(key string) time.Duration
+		GetFloat64(key string) float64
+		GetInt(key string) int
+		GetInt64(key string) int64
+		GetString(key string) string
+		GetStringMap(key string) map[string]interface{}
+		GetStringMapString(key string) map[string]string
+		GetStringSlice(key string) []string
+		GetTime(key string) time.Time
+		IsSet(key string) bool
+		AllKeys() []string
+		AllSettings() map[string]interface{}
+		Unmarshal(rawVal interface{}, opts ...viper.DecoderConfigOption) error
+		UnmarshalKey(key string, rawVal interface{}, opts ...viper.DecoderConfigOption) error
+	}
+
+	// View is bundle config accessor, it forwards getters to the viper instance
+	// and guards mutators according to the bundle read-only mode.
+	View struct {
+		bundle *Bundle
+	}
+
+	// readOnlyMode is mode of config mutators.
+	readOnlyMode int
+)
+
+var (
+	// compile time checks.
+	_ Config = (*View)(nil)
+	_ Config = (*viper.Viper)(nil)
+)
+
+const (
+	// readOnlyNone allows config mutation.
+	readOnlyNone readOnlyMode = iota
+
+	// readOnlyError makes mutators fail with ErrReadOnly.
+	readOnlyError
+
+	// readOnlyNoop makes mutators no-ops.
+	readOnlyNoop
+)
+
+// ReadOnly option forbids config mutation through bundle and its view, mutators fail with ErrReadOnly.
+func ReadOnly() Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.readOnly = readOnlyError
+	})
+}
+
+// ReadOnlyNoop option forbids config mutation through bundle and its view, mutators are no-ops.
+func ReadOnlyNoop() Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.readOnly = readOnlyNoop
+	})
+}
+
+// View returns bundle config view.
+func (b *Bundle) View() *View {
+	return &View{bundle: b}
+}
+
+// Set sets the value for the key in the override register, unless config is read-only.
+func (b *Bundle) Set(key string, value interface{}) error {
+	return b.mutate(func(v *viper.Viper) error {
+		v.Set(b.nsKey(key), value)
+		return nil
+	})
+}
+
+// MergeConfigMap merges the configuration from the map given with an existing config, unless config is read-only.
+func (b *Bundle) MergeConfigMap(cfg map[string]interface{}) error {
+	return b.mutate(func(v *viper.Viper) error {
+		return v.MergeConfigMap(b.nsMap(cfg))
+	})
+}
+
+// Merge deep merges all settings of the other bundle into this one, unless config is read-only, e.g. to extend
+// library bundle shipped pre-configured. This bundle wins on conflicts, the values of keys missing in its
+// settings are set as defaults, so they are kept on config reload and overridden by any other source.
+func (b *Bundle) Merge(other *Bundle) error {
+	var unlock = other.rlock("")
+	var settings = flatten(other.viper.AllSettings())
+	unlock()
+
+	return b.mutate(func(v *viper.Viper) error {
+		var current = v.AllSettings()
+		for key, value := range settings {
+			if !occupied(current, key) {
+				b.setDefault(key, value)
+			}
+		}
+
+		return nil
+	})
+}
+
+// occupied reports whether the key or any of its parent keys holds a value in nested settings map.
+func occupied(settings map[string]interface{}, key string) bool {
+	var node = settings
+	for _, part := range strings.Split(key, keyDelimiter) {
+		var value, ok = node[part]
+		if !ok {
+			return false
+		}
+
+		if node, ok = value.(map[string]interface{}); !ok {
+			return true
+		}
+	}
+
+	return true
+}
+
+// Override sets the value for the key in the override register, e.g. in subcommand pre-run before services
+// are resolved. Unlike Set it ignores the read-only mode, as it is intended for the application itself.
+func (b *Bundle) Override(key string, value interface{}) {
+	b.ensureLoaded()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.viper.Set(b.nsKey(key), value)
+}
+
+// OverrideMap option sets the values for the keys in the override register on viper provide,
+// it is applied after all other config sources.
+func OverrideMap(values map[string]interface{}) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.overrides = append(bundle.overrides, values)
+	})
+}
+
+// mutate calls fn according to the read-only mode.
+func (b *Bundle) mutate(fn func(v *viper.Viper) error) error {
+	switch b.readOnly {
+	case readOnlyError:
+		return ErrReadOnly
+	case readOnlyNoop:
+		return nil
+	default:
+		b.ensureLoaded()
+
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		return fn(b.viper)
+	}
+}
+
+// rlock locks bundle for reading and returns the unlock function, lazy config is loaded and the stale
+// remote value of the key is re-read before. The empty key skips remote value re-read.
+func (b *Bundle) rlock(key string) func() {
+	b.ensureLoaded()
+
+	if len(key) > 0 {
+		b.refreshRemoteKey(key)
+	}
+
+	b.mu.RLock()
+
+	return b.mu.RUnlock
+}
+
+// viewGet calls viper getter with the namespaced key under bundle read lock, so getters don't race
+// with config mutation and reload.
+func viewGet[T any](v *View, key string, getter func(vp *viper.Viper, key string) T) T {
+	key = v.bundle.nsKey(key)
+	defer v.bundle.rlock(key)()
+
+	return getter(v.bundle.viper, key)
+}
+
+func (b *Bundle) provideView(_ *viper.Viper) *View {
+	return b.View()
+}
+
+// Get forwards to viper.Viper.Get.
+func (v *View) Get(key string) interface{} { return viewGet(v, key, (*viper.Viper).Get) }
+
+// GetBool forwards to viper.Viper.GetBool.
+func (v *View) GetBool(key string) bool { return viewGet(v, key, (*viper.Viper).GetBool) }
+
+// GetDuration forwards to viper.Viper.GetDuration.
+func (v *View) GetDuration(key string) time.Duration {
+	return viewGet(v, key, (*viper.Viper).GetDuration)
+}
+
+// GetFloat64 forwards to viper.Viper.GetFloat64.
+func (v *View) GetFloat64(key string) float64 {
+	return viewGet(v, key, (*viper.Viper).GetFloat64)
+}
+
+// GetInt forwards to viper.Viper.GetInt.
+func (v *View) GetInt(key string) int { return viewGet(v, key, (*viper.Viper).GetInt) }
+
+// GetInt32 forwards to viper.Viper.GetInt32.
+func (v *View) GetInt32(key string) int32 { return viewGet(v, key, (*viper.Viper).GetInt32) }
+
+// GetInt64 forwards to viper.Viper.GetInt64.
+func (v *View) GetInt64(key string) int64 { return viewGet(v, key, (*viper.Viper).GetInt64) }
+
+// GetIntSlice forwards to viper.Viper.GetIntSlice.
+func (v *View) GetIntSlice(key string) []int {
+	return viewGet(v, key, (*viper.Viper).GetIntSlice)
+}
+
+// GetSizeInBytes forwards to viper.Viper.GetSizeInBytes.
+func (v *View) GetSizeInBytes(key string) uint {
+	return viewGet(v, key, (*viper.Viper).GetSizeInBytes)
+}
+
+// GetString forwards to viper.Viper.GetString.
+func (v *View) GetString(key string) string { return viewGet(v, key, (*viper.Viper).GetString) }
+
+// GetStringMap forwards to viper.Viper.GetStringMap.
+func (v *View) GetStringMap(key string) map[string]interface{} {
+	return viewGet(v, key, (*viper.Viper).GetStringMap)
+}
+
+// GetStringMapString forwards to viper.Viper.GetStringMapString.
+func (v *View) GetStringMapString(key string) map[string]string {
+	return viewGet(v, key, (*viper.Viper).GetStringMapString)
+}
+
+// GetStringMapStringSlice forwards to viper.Viper.GetStringMapStringSlice.
+func (v *View) GetStringMapStringSlice(key string) map[string][]string {
+	return viewGet(v, key, (*viper.Viper).GetStringMapStringSlice)
+}
+
+// GetStringSlice forwards to viper.Viper.GetStringSlice.
+func (v *View) GetStringSlice(key string) []string {
+	return viewGet(v, key, (*viper.Viper).GetStringSlice)
+}
+
+// GetTime forwards to viper.Viper.GetTime.
+func (v *View) GetTime(key string) time.Time { return viewGet(v, key, (*viper.Viper).GetTime) }
+
+// GetUint forwards to viper.Viper.GetUint.
+func (v *View) GetUint(key string) uint { return viewGet(v, key, (*viper.Viper).GetUint) }
+
+// GetUint16 forwards to viper.Viper.GetUint16.
+func (v *View) GetUint16(key string) uint16 { return viewGet(v, key, (*viper.Viper).GetUint16) }
+
+// GetUint32 forwards to viper.Viper.GetUint32.
+func (v *View) GetUint32(key string) uint32 { return viewGet(v, key, (*viper.Viper).GetUint32) }
+
+// GetUint64 forwards to viper.Viper.GetUint64.
+func (v *View) GetUint64(key string) uint64 { return viewGet(v, key, (*viper.Viper).GetUint64) }
+
+// IsSet forwards to viper.Viper.IsSet.
+func (v *View) IsSet(key string) bool { return viewGet(v, key, (*viper.Viper).IsSet) }
+
+// InConfig forwards to viper.Viper.InConfig.
+func (v *View) InConfig(key string) bool { return viewGet(v, key, (*viper.Viper).InConfig) }
+
+// AllKeys forwards to viper.Viper.AllKeys, keys are relative to the namespace.
+func (v *View) AllKeys() []string {
+	defer v.bundle.rlock("")()
+	return v.bundle.nsKeys(v.bundle.viper.AllKeys())
+}
+
+// AllSettings forwards to viper.Viper.AllSettings, settings are scoped to the namespace.
+func (v *View) AllSettings() map[string]interface{} {
+	defer v.bundle.rlock("")()
+	return v.bundle.nsSettings(v.bundle.viper.AllSettings())
+}
+
+// ConfigFileUsed forwards to viper.Viper.ConfigFileUsed.
+func (v *View) ConfigFileUsed() string {
+	defer v.bundle.rlock("")()
+	return v.bundle.viper.ConfigFileUsed()
+}
+
+// Unmarshal forwards to viper.Viper.Unmarshal, the namespace subtree is unmarshalled.
+func (v *View) Unmarshal(rawVal interface{}, opts ...viper.DecoderConfigOption) error {
+	defer v.bundle.rlock(v.bundle.namespace)()
+
+	if len(v.bundle.namespace) > 0 {
+		return v.bundle.viper.UnmarshalKey(v.bundle.namespace, rawVal, opts...)
+	}
+
+	return v.bundle.viper.Unmarshal(rawVal, opts...)
+}
+
+// UnmarshalKey forwards to viper.Viper.UnmarshalKey.
+func (v *View) UnmarshalKey(key string, rawVal interface{}, opts ...viper.DecoderConfigOption) error {
+	return viewGet(v, key, func(vp *viper.Viper, key string) error {
+		return vp.UnmarshalKey(key, rawVal, opts...)
+	})
+}
+
+// UnmarshalExact forwards to viper.Viper.UnmarshalExact, the namespace subtree is unmarshalled.
+func (v *View) UnmarshalExact(rawVal interface{}, opts ...viper.DecoderConfigOption) error {
+	defer v.bundle.rlock("")()
+
+	if len(v.bundle.namespace) > 0 {
+		var sub = v.bundle.viper.Sub(v.bundle.namespace)
+		if sub == nil {
+			sub = viper.New()
+		}
+
+		return sub.UnmarshalExact(rawVal, opts...)
+	}
+
+	return v.bundle.viper.UnmarshalExact(rawVal, opts...)
+}
+
+// Set sets the value for the key in the override register, unless config is read-only.
+func (v *View) Set(key string, value interface{}) error { return v.bundle.Set(key, value) }
+
+// SetDefault sets the default value for the key, unless config is read-only.
+func (v *View) SetDefault(key string, value interface{}) error {
+	return v.bundle.mutate(func(vp *viper.Viper) error {
+		vp.SetDefault(key, value)
+		return nil
+	})
+}
+
+// MergeConfig merges a new configuration with an existing config, unless config is read-only.
+func (v *View) MergeConfig(in io.Reader) error {
+	return v.bundle.mutate(func(vp *viper.Viper) error {
+		return vp.MergeConfig(in)
+	})
+}
+
+// MergeConfigMap merges the configuration from the map given with an existing config, unless config is read-only.
+func (v *View) MergeConfigMap(cfg map[string]interface{}) error {
+	return v.bundle.MergeConfigMap(cfg)
+}
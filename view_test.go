@@ -0,0 +1,144 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gozix/di"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_ReadOnly(t *testing.T) {
+	var (
+		dir = configDir(t, map[string]string{"config.json": `{"name": "app", "port": 8080}`})
+		b   = NewBundle(ReadOnly())
+	)
+
+	var _, err = provide(t, b, dir)
+	require.NoError(t, err)
+
+	var view = b.View()
+	assert.ErrorIs(t, b.Set("name", "changed"), ErrReadOnly)
+	assert.ErrorIs(t, b.MergeConfigMap(map[string]interface{}{"name": "changed"}), ErrReadOnly)
+	assert.ErrorIs(t, view.Set("name", "changed"), ErrReadOnly)
+	assert.ErrorIs(t, view.SetDefault("extra", true), ErrReadOnly)
+	assert.ErrorIs(t, view.MergeConfig(strings.NewReader(`{"name": "changed"}`)), ErrReadOnly)
+	assert.ErrorIs(t, view.MergeConfigMap(map[string]interface{}{"name": "changed"}), ErrReadOnly)
+
+	assert.Equal(t, "app", view.GetString("name"))
+	assert.Equal(t, 8080, view.GetInt("port"))
+	assert.False(t, view.IsSet("extra"))
+}
+
+func TestBundle_ReadOnlyNoop(t *testing.T) {
+	var (
+		dir = configDir(t, map[string]string{"config.json": `{"name": "app"}`})
+		b   = NewBundle(ReadOnlyNoop())
+	)
+
+	var _, err = provide(t, b, dir)
+	require.NoError(t, err)
+
+	assert.NoError(t, b.Set("name", "changed"))
+	assert.NoError(t, b.View().SetDefault("extra", true))
+	assert.Equal(t, "app", b.View().GetString("name"))
+	assert.False(t, b.View().IsSet("extra"))
+}
+
+func TestBundle_Set(t *testing.T) {
+	var b = NewBundle()
+
+	var _, err = provide(t, b, configDir(t, map[string]string{"config.json": `{"name": "app"}`}))
+	require.NoError(t, err)
+	require.NoError(t, b.Set("name", "changed"))
+	assert.Equal(t, "changed", b.View().GetString("name"))
+}
+
+func TestBundle_Override(t *testing.T) {
+	t.Setenv("ENV_DB_HOST", "env")
+
+	var (
+		dir = configDir(t, map[string]string{"config.json": `{"db": {"host": "file", "port": 5432}}`})
+		b   = NewBundle(ReadOnly(), OverrideMap(map[string]interface{}{"db.host": "override", "debug": true}))
+		ctn = buildContainer(t, dir, b)
+	)
+
+	var config Config
+	require.NoError(t, ctn.Resolve(&config))
+	assert.Equal(t, "override", config.GetString("db.host"), "override map must win over env")
+	assert.True(t, config.GetBool("debug"))
+
+	b.Override("db.port", 6432)
+	assert.Equal(t, 6432, config.GetInt("db.port"), "override must ignore read-only mode")
+	assert.ErrorIs(t, b.Set("db.port", 7432), ErrReadOnly)
+}
+
+func TestBundle_ProvideConfig(t *testing.T) {
+	var (
+		dir = configDir(t, map[string]string{
+			"config.json":        `{"name": "app", "db": {"port": 5432}}`,
+			"plugin-config.json": `{"name": "plugin"}`,
+		})
+		ctn = buildContainer(t, dir, NewBundle())
+	)
+
+	var config Config
+	require.NoError(t, ctn.Resolve(&config))
+	assert.IsType(t, &View{}, config)
+	assert.Equal(t, "app", config.GetString("name"))
+	assert.Equal(t, 5432, config.GetInt("db.port"))
+	assert.True(t, config.IsSet("db.port"))
+
+	var db struct {
+		Port int `mapstructure:"port"`
+	}
+
+	require.NoError(t, config.UnmarshalKey("db", &db))
+	assert.Equal(t, 5432, db.Port)
+
+	ctn = buildContainer(t, dir, NewBundle(InstanceName("plugin"), ConfigName("plugin-config")))
+
+	var pluginConfig Config
+	require.NoError(t, ctn.Resolve(&pluginConfig, di.WithTags(InstanceTag("plugin"))))
+	assert.Equal(t, "plugin", pluginConfig.GetString("name"))
+}
+
+func TestBundle_Merge(t *testing.T) {
+	var (
+		libDir = configDir(t, map[string]string{
+			"config.json": `{"db": {"host": "lib", "port": 5432, "pool": {"size": 10, "idle": 2}}, "debug": {"enabled": true}}`,
+		})
+		appDir = configDir(t, map[string]string{
+			"config.json": `{"db": {"host": "app", "pool": {"size": 20}}, "debug": false}`,
+		})
+		lib = NewBundle()
+		app = NewBundle()
+	)
+
+	var _, err = provide(t, lib, libDir)
+	require.NoError(t, err)
+
+	v, err := provide(t, app, appDir)
+	require.NoError(t, err)
+
+	require.NoError(t, app.Merge(lib))
+	assert.Equal(t, "app", v.GetString("db.host"), "receiver must win on conflicts")
+	assert.Equal(t, 20, v.GetInt("db.pool.size"))
+	assert.Equal(t, 5432, v.GetInt("db.port"), "missing nested key must be merged")
+	assert.Equal(t, 2, v.GetInt("db.pool.idle"))
+	assert.False(t, v.GetBool("debug"), "leaf value must not be replaced by nested map")
+	assert.False(t, v.IsSet("debug.enabled"))
+
+	require.NoError(t, app.Set("db.port", 6432))
+	assert.Equal(t, 6432, v.GetInt("db.port"), "merged values must be overridden by other sources")
+
+	var readOnly = NewBundle(ReadOnly())
+	_, err = provide(t, readOnly, appDir)
+	require.NoError(t, err)
+	assert.ErrorIs(t, readOnly.Merge(lib), ErrReadOnly)
+}
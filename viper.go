@@ -6,14 +6,22 @@
 package viper
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/gozix/di"
 	"github.com/gozix/glue/v3"
+	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
@@ -26,23 +34,192 @@ type (
 
 	// Bundle implements the glue.Bundle interface.
 	Bundle struct {
+		options           []Option
 		viper             *viper.Viper
 		dontUseConfigFile bool
+		onChange          []func(in fsnotify.Event)
+		watchDebounce     time.Duration
+		flagSets          []*pflag.FlagSet
+		secrets           []string
+		configType        string
+		includes          bool
+		normalizeKeys     bool
+		config            map[string]interface{}
+		decodeHooks       []mapstructure.DecodeHookFunc
+		tagName           string
+		remoteProviders   []remoteProvider
+		remoteAttempts    int
+		remoteBackoff     time.Duration
+		defaultFuncs      []defaultFunc
+		onDiff            []func(changes []KeyChange)
+		settings          map[string]interface{}
+		splitKeyDirs      []string
+		envPrefix         string
+		envKeyReplacer    *strings.Replacer
+		envStrict         bool
+		defaults          map[string]interface{}
+		watchCancel       context.CancelFunc
+		watchDone         <-chan struct{}
+		debounceDone      <-chan struct{}
+		provideTags       []string
+		readOnly          readOnlyMode
+		configName        string
+		configPaths       []string
+		configEnvJSON     []string
+		automaticEnv      bool
+		envSnapshot       map[string]string
+		enums             []enum
+		configNames       []string
+		required          []string
+		loaded            bool
+		flagNormalizer    func(name string) string
+		fileConfig        map[string]interface{}
+		configFileFound   bool
+		embeds            []embed
+		filePreferred     []string
+		remoteMergeAll    bool
+		remoteConfig      map[string]interface{}
+		templateValues    bool
+		configPerm        os.FileMode
+		lazy              bool
+		lazyMu            sync.Mutex
+		lazyLoad          func() error
+		lazyErr           error
+		lazyDone          bool
+		captureTo         string
+		replayFrom        string
+		noDurationHook    bool
+		noWeakInput       bool
+		namespace         string
+		strictSearch      bool
+		flagSet           *pflag.FlagSet
+		fsys              fs.FS
+		envBindings       map[string]string
+		sliceEnvKeys      []string
+		defaultsErr       error
+		watchAllPaths     bool
+		configValueFlag   bool
+		onLoad            []func(v *viper.Viper) error
+		envPriorities     []envPriority
+		overrides         []map[string]interface{}
+		mu                sync.RWMutex
+		charset           string
+		unusedTargets     []interface{}
+		remaps            []remap
+		streamLarge       bool
+		sources           []Source
+		configEnvBase64   []envBase64
+		reportMerges      func(key string, from, to string)
+		mergeLayers       []mergeLayer
+		remoteLayers      []mergeLayer
+		whenDefaults      []conditionalDefault
+		whenReplaced      map[string]interface{}
+		maxConfigSize     int64
+		subFiles          []subFile
+		watchStops        []func()
+		remoteKeyTTLs     []*remoteKeyTTL
+		envFoldCase       bool
+		keyTypes          []keyType
+		rewritten         map[string]interface{}
+		pathKeys          []string
+		parsedConfig      map[string]interface{}
+		boolEnvKeys       []string
+		printConfigFormat string
+		structEnvKeys     []string
+		historyKeys       []string
+		historyDepth      int
+		history           map[string][]interface{}
+		newestDir         string
+		newestPattern     string
+		envAliases        []envPriority
+		strictPaths       bool
+		instanceName      string
+		requireEnvRefs    bool
+		diffStreams       []chan []KeyChange
+		watchCtx          context.Context
+		argOverrides      bool
+	}
+
+	// defaultFunc is lazily evaluated default value of key.
+	defaultFunc struct {
+		key string
+		fn  func() interface{}
+	}
+
+	// conditionalDefault is default value of key applied if condition is met.
+	conditionalDefault struct {
+		key   string
+		when  func(v *viper.Viper) bool
+		value interface{}
+	}
+
+	// envPriority is key bound to env variables in priority order.
+	envPriority struct {
+		key   string
+		names []string
 	}
 
 	// optionFunc wraps a func, so it satisfies the Option interface.
 	optionFunc func(bundle *Bundle)
 )
 
-// ErrUndefinedAppPath is error, triggered when app.path is undefined in current context.
-var ErrUndefinedAppPath = errors.New("app.path is undefined")
+var (
+	// ErrUndefinedAppPath is error, triggered when app.path is undefined in current context.
+	ErrUndefinedAppPath = errors.New("app.path is undefined")
+
+	// ErrUndefinedKey is error, triggered when requested key is undefined in config.
+	ErrUndefinedKey = errors.New("key is undefined")
+
+	// ErrReadOnly is error, triggered when read-only config is mutated.
+	ErrReadOnly = errors.New("config is read-only")
+
+	// ErrNotLoaded is error, triggered when config is not loaded yet.
+	ErrNotLoaded = errors.New("config is not loaded")
+
+	// ErrNoConfigFile is error, triggered when config is saved, but no config file was used.
+	ErrNoConfigFile = errors.New("config file is not used")
+
+	// ErrAmbiguousConfigFile is error, triggered when several config files match the same base name.
+	ErrAmbiguousConfigFile = errors.New("config file is ambiguous")
+
+	// ErrInvalidPrototype is error, triggered when config shape prototype isn't a struct.
+	ErrInvalidPrototype = errors.New("prototype is not a struct")
+
+	// ErrConfigTooLarge is error, triggered when config file exceeds max config size.
+	ErrConfigTooLarge = errors.New("config file is too large")
+
+	// ErrConfigPrinted is error, triggered when effective config is printed by --print-config flag
+	// instead of running command.
+	ErrConfigPrinted = errors.New("config is printed")
+
+	// ErrInvalidConfigPath is error, triggered when config search directory doesn't exist or isn't a directory.
+	ErrInvalidConfigPath = errors.New("invalid config path")
+
+	// ErrIncompatibleConfig is error, triggered when config files have different shape.
+	ErrIncompatibleConfig = errors.New("config is incompatible")
+
+	// ErrLazyViper is error, triggered when viper instance is resolved in lazy mode, Config must be resolved instead.
+	ErrLazyViper = errors.New("viper instance is not provided in lazy mode")
+)
 
 const (
 	// BundleName is default definition name.
 	BundleName = "viper"
 
+	// TagFlagSet is tag marks the flag set the bundle registers, it is used to resolve the flag set.
+	TagFlagSet = tagViperFlagSet
+
+	// TagFS is tag marks fs.FS definition, config files are read from it instead of OS filesystem.
+	TagFS = "viper.fs"
+
+	// tagViper is tag marks bundle viper instance.
+	tagViper = "viper.viper"
+
 	// tagViperFlagSet is tag marks bundle flag set.
 	tagViperFlagSet = "viper.flag_set"
+
+	// keyDelimiter is viper key delimiter.
+	keyDelimiter = "."
 )
 
 // NewBundle create bundle instance.
@@ -63,37 +240,166 @@ func NewBundle(options ...Option) *Bundle {
 // NewBundleWithConfig create bundle instance with config.
 func NewBundleWithConfig(options ...Option) *Bundle {
 	var bundle = Bundle{
-		viper: viper.New(),
+		options: options,
 	}
 
-	for _, option := range options {
-		option.apply(&bundle)
-	}
+	bundle.init()
 
 	return &bundle
 }
 
+// NewBundleWithDefaults create bundle instance with defaults read from the data of config type,
+// e.g. embedded with go:embed. The defaults are applied before options, so config file, env
+// variables, flags and Default options take precedence over them. Parse error of the data
+// fails viper provide.
+func NewBundleWithDefaults(defaults []byte, configType string, options ...Option) *Bundle {
+	return NewBundle(append([]Option{defaultsData(defaults, configType)}, options...)...)
+}
+
+// defaultsData option sets defaults read from the data of config type.
+func defaultsData(data []byte, configType string) Option {
+	return optionFunc(func(bundle *Bundle) {
+		var v = viper.New()
+		v.SetConfigType(configType)
+
+		if err := v.ReadConfig(bytes.NewReader(data)); err != nil {
+			bundle.defaultsErr = fmt.Errorf("unable to read defaults : %w", err)
+			return
+		}
+
+		for key, value := range flatten(v.AllSettings()) {
+			bundle.setDefault(key, value)
+		}
+	})
+}
+
 // AutomaticEnv option.
 func AutomaticEnv() Option {
 	return optionFunc(func(bundle *Bundle) {
-		bundle.viper.AutomaticEnv()
+		bundle.automaticEnv = true
 	})
 }
 
-// EnvPrefix option.
+// EnvPrefix option sets prefix of env variables, the last option wins. An empty value clears
+// the prefix, so automatic env binds unprefixed names like DATABASE_URL.
 func EnvPrefix(value string) Option {
 	return optionFunc(func(bundle *Bundle) {
-		bundle.viper.SetEnvPrefix(value)
+		bundle.envPrefix = value
 	})
 }
 
 // EnvKeyReplacer option.
 func EnvKeyReplacer(value *strings.Replacer) Option {
 	return optionFunc(func(bundle *Bundle) {
+		bundle.envKeyReplacer = value
 		bundle.viper.SetEnvKeyReplacer(value)
 	})
 }
 
+// Env option binds key to env variable and sets default value for the key, the env variable
+// value takes precedence over the default one when set.
+func Env(key string, envVar string, def interface{}) Option {
+	return optionFunc(func(bundle *Bundle) {
+		_ = bundle.viper.BindEnv(key, envVar)
+		bundle.setDefault(key, def)
+
+		if bundle.envBindings == nil {
+			bundle.envBindings = make(map[string]string)
+		}
+
+		bundle.envBindings[strings.ToLower(key)] = envVar
+	})
+}
+
+// Required option validates on viper provide the keys are set.
+func Required(keys ...string) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.required = append(bundle.required, keys...)
+	})
+}
+
+// BindEnvPriority option sets key to the value of the first present env variable of envVars
+// on viper provide, so earlier variables take precedence over later ones.
+func BindEnvPriority(key string, envVars ...string) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.envPriorities = append(bundle.envPriorities, envPriority{key: key, names: envVars})
+	})
+}
+
+// EnvAlias option binds key to its env variable and the alias env variables in priority order, e.g. legacy
+// DATABASE_URL for "db.dsn" key. Unlike BindEnvPriority values keep env precedence and unlike viper aliases
+// config file key names aren't affected.
+func EnvAlias(key string, envVars ...string) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.envAliases = append(bundle.envAliases, envPriority{key: strings.ToLower(key), names: envVars})
+	})
+}
+
+// SliceEnvKeys option splits comma separated env values of keys into string slices,
+// so ENV_HOSTS=a,b,c is resolved as []string{"a", "b", "c"}.
+func SliceEnvKeys(keys ...string) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.sliceEnvKeys = append(bundle.sliceEnvKeys, keys...)
+	})
+}
+
+// BoolEnvKeys option coerces env values yes/no, on/off, 1/0 and true/false of keys into booleans
+// case-insensitively, so ENV_ENABLED=yes is resolved as true. Other values are kept as is.
+func BoolEnvKeys(keys ...string) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.boolEnvKeys = append(bundle.boolEnvKeys, keys...)
+	})
+}
+
+// EnvStrict option validates env values of keys with default value against the default value type
+// and fails viper provide with all found mismatches, instead of silently returning zero values.
+func EnvStrict() Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.envStrict = true
+	})
+}
+
+// EnvSnapshot option captures environment on option apply and uses it instead of live environment,
+// so later changes of process environment don't affect the bundle.
+//
+// Viper reads live environment only, so with automatic env the captured values of known keys
+// (set in config or defaults) are applied as overrides after config is read, changed bound flags
+// take precedence over them.
+func EnvSnapshot() Option {
+	return optionFunc(func(bundle *Bundle) {
+		var environ = os.Environ()
+
+		bundle.envSnapshot = make(map[string]string, len(environ))
+		for _, pair := range environ {
+			if name, value, ok := strings.Cut(pair, "="); ok {
+				bundle.envSnapshot[name] = value
+			}
+		}
+	})
+}
+
+// CaseInsensitiveEnvPrefix option matches env prefix and key of automatic env case-insensitively,
+// e.g. app_db_host resolves db.host with prefix APP, for platforms passing env variables lowercased
+// or in mixed case.
+//
+// Viper matches env names case-sensitively, so values of known keys (set in config or defaults) found
+// by case-insensitive match only are applied as overrides after config is read, changed bound flags
+// take precedence over them. The exact name wins over other matches, names which differ in case only
+// are ambiguous and the first of them in environment is used. Env variables are case-insensitive
+// on Windows already.
+func CaseInsensitiveEnvPrefix() Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.envFoldCase = true
+	})
+}
+
+// ConfigEnvJSON option merges JSON config from env variable on top of config file.
+func ConfigEnvJSON(name string) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.configEnvJSON = append(bundle.configEnvJSON, name)
+	})
+}
+
 // ConfigFile option.
 func ConfigFile(value string) Option {
 	return optionFunc(func(bundle *Bundle) {
@@ -104,13 +410,22 @@ func ConfigFile(value string) Option {
 // ConfigName option.
 func ConfigName(value string) Option {
 	return optionFunc(func(bundle *Bundle) {
+		bundle.configName = value
 		bundle.viper.SetConfigName(value)
 	})
 }
 
+// ConfigNames option sets config file base names, they are tried in order and the first found is read.
+func ConfigNames(names ...string) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.configNames = append(bundle.configNames, names...)
+	})
+}
+
 // ConfigPath option.
 func ConfigPath(value string) Option {
 	return optionFunc(func(bundle *Bundle) {
+		bundle.configPaths = append(bundle.configPaths, value)
 		bundle.viper.AddConfigPath(value)
 	})
 }
@@ -118,10 +433,28 @@ func ConfigPath(value string) Option {
 // ConfigType option.
 func ConfigType(value string) Option {
 	return optionFunc(func(bundle *Bundle) {
+		bundle.configType = value
 		bundle.viper.SetConfigType(value)
 	})
 }
 
+// ConfigPermissions option sets permissions of saved config file, by default permissions
+// of the existing file are kept.
+func ConfigPermissions(perm os.FileMode) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.configPerm = perm
+		bundle.viper.SetConfigPermissions(perm)
+	})
+}
+
+// MaxConfigSize option limits size of config file in bytes, a larger file fails config read before
+// it is read. By default size is unlimited.
+func MaxConfigSize(bytes int64) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.maxConfigSize = bytes
+	})
+}
+
 // DontUseConfigFile option disables config file reading.
 func DontUseConfigFile() Option {
 	return optionFunc(func(bundle *Bundle) {
@@ -129,66 +462,898 @@ func DontUseConfigFile() Option {
 	})
 }
 
+// EnvOnly option enables environment-only mode for deployments without config file, config file
+// isn't searched and read, and values are resolved by automatic env, defaults and overrides only.
+func EnvOnly() Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.dontUseConfigFile = true
+		bundle.automaticEnv = true
+	})
+}
+
 // Default option sets default value for key in viper instance.
 func Default(key string, value interface{}) Option {
 	return optionFunc(func(bundle *Bundle) {
-		bundle.viper.SetDefault(key, value)
+		bundle.setDefault(key, value)
+	})
+}
+
+// DefaultFunc option sets default value for key returned by fn, if key is unset.
+// The fn is called once config is read on viper provide, not on bundle construction.
+func DefaultFunc(key string, fn func() interface{}) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.defaultFuncs = append(bundle.defaultFuncs, defaultFunc{key: key, fn: fn})
 	})
 }
 
+// ConditionalDefault option sets default value for key, if when reports true and key is unset or has
+// a static default value only. The when is called once config is read on viper provide and on every
+// reload, so it sees loaded values, e.g. of mode key. The first matching conditional default of key wins.
+func ConditionalDefault(key string, when func(v *viper.Viper) bool, value interface{}) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.whenDefaults = append(bundle.whenDefaults, conditionalDefault{
+			key:   strings.ToLower(key),
+			when:  when,
+			value: value,
+		})
+	})
+}
+
+// BindFlags option binds changed flags of flag set to viper keys, by default dashes in flag names
+// are translated to the key delimiter, so "--log-level" flag is bound to "log.level" key.
+func BindFlags(flagSet *pflag.FlagSet) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.flagSets = append(bundle.flagSets, flagSet)
+	})
+}
+
+// FlagNormalizer option sets function translating flag names to viper keys on flags binding.
+func FlagNormalizer(fn func(name string) string) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.flagNormalizer = fn
+	})
+}
+
+// OnChange option registers handler called after the config file was changed and re-read.
+// Registering at least one handler enables config file watching.
+func OnChange(handler func(in fsnotify.Event)) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.onChange = append(bundle.onChange, handler)
+	})
+}
+
+// OnLoad option adds hook called after config is loaded and after every reload, it enables config
+// file watching. Errors of all hooks are aggregated and fail the load, a failed reload keeps
+// the last good config.
+func OnLoad(fn func(v *viper.Viper) error) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.onLoad = append(bundle.onLoad, fn)
+	})
+}
+
+// OnDiff option registers handler called with changed keys after the config file was changed and re-read.
+// Registering at least one handler enables config file watching.
+func OnDiff(handler func(changes []KeyChange)) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.onDiff = append(bundle.onDiff, handler)
+	})
+}
+
+// WatchAllPaths option watches all config search paths instead of the found config file directory only,
+// so a config file appeared in a higher priority path takes over and the config is reloaded.
+func WatchAllPaths() Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.watchAllPaths = true
+	})
+}
+
+// WatchDebounce option coalesces config file events, so the OnChange handlers are called
+// at most once per window after the last received event.
+func WatchDebounce(d time.Duration) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.watchDebounce = d
+	})
+}
+
+// Includes option enables resolving of "$include" directives after the config file is read.
+// The directive value is a path or a list of paths relative to the including file directory,
+// included files are merged beneath the key containing directive, own keys take precedence.
+func Includes() Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.includes = true
+	})
+}
+
+// SplitKeyDir option merges config from directory, where every file is a key and its content is a value,
+// like mounted Kubernetes ConfigMap. Dots or double underscores in file names denote nested keys,
+// so both "db.host" and "db__host" files are "db.host" key. Hidden files are skipped.
+func SplitKeyDir(path string) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.splitKeyDirs = append(bundle.splitKeyDirs, path)
+	})
+}
+
+// NormalizeKeys option guarantees keys of config file are lowercased recursively after it is read,
+// including keys of maps nested in lists, which viper keeps as is.
+//
+// Viper matches keys case-insensitively and the bundle has no case-sensitive mode, so the option
+// doesn't conflict with any other option.
+func NormalizeKeys() Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.normalizeKeys = true
+	})
+}
+
+// DisableDurationHook option stops decoding of duration strings like "5s" to time.Duration on
+// bundle unmarshal, the hook is registered by default.
+func DisableDurationHook() Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.noDurationHook = true
+	})
+}
+
+// WeaklyTypedInput option enables weakly typed decoding on bundle unmarshal, so string env values
+// like "3" or "true" are decoded into int and bool fields. It is enabled by default like in viper,
+// the option reverts DisableWeaklyTypedInput.
+func WeaklyTypedInput() Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.noWeakInput = false
+	})
+}
+
+// DisableWeaklyTypedInput option stops weakly typed decoding on bundle unmarshal. Weak typing accepts
+// lossy conversions, e.g. empty string to zero, number to bool and single value to slice.
+func DisableWeaklyTypedInput() Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.noWeakInput = true
+	})
+}
+
+// DecodeHook option adds decode hook used by bundle on decoding config values.
+func DecodeHook(hook mapstructure.DecodeHookFunc) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.decodeHooks = append(bundle.decodeHooks, hook)
+	})
+}
+
+// TagName option sets struct tag name used by bundle on decoding config values, default is "mapstructure".
+func TagName(value string) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.tagName = value
+	})
+}
+
+// ProvideTag option adds tag to viper instance definition, so it can be resolved by tag
+// among other provided viper instances. The instance is still resolvable without tag.
+func ProvideTag(tag string) Option {
+	return optionFunc(func(bundle *Bundle) {
+		bundle.provideTags = append(bundle.provideTags, tag)
+	})
+}
+
+// Reset recreates viper instance and re-applies construction options, so all runtime changes are discarded.
+// Config file watching is stopped, the new viper instance must be provided again to read config.
+// The flag set and file system provided by the container are kept.
+func (b *Bundle) Reset() {
+	b.stopWatch()
+
+	b.lazyMu.Lock()
+	defer b.lazyMu.Unlock()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.reset()
+	b.init()
+}
+
+// reset sets the bundle state to zero values except options, locks and container dependencies,
+// the caller must hold the bundle locks.
+func (b *Bundle) reset() {
+	b.viper = nil
+	b.dontUseConfigFile = false
+	b.onChange = nil
+	b.watchDebounce = 0
+	b.flagSets = nil
+	b.secrets = nil
+	b.configType = ""
+	b.includes = false
+	b.normalizeKeys = false
+	b.config = nil
+	b.decodeHooks = nil
+	b.tagName = ""
+	b.remoteProviders = nil
+	b.remoteAttempts = 0
+	b.remoteBackoff = 0
+	b.defaultFuncs = nil
+	b.onDiff = nil
+	b.settings = nil
+	b.splitKeyDirs = nil
+	b.envPrefix = ""
+	b.envKeyReplacer = nil
+	b.envStrict = false
+	b.defaults = nil
+	b.watchCancel = nil
+	b.watchDone = nil
+	b.debounceDone = nil
+	b.provideTags = nil
+	b.readOnly = readOnlyNone
+	b.configName = ""
+	b.configPaths = nil
+	b.configEnvJSON = nil
+	b.automaticEnv = false
+	b.envSnapshot = nil
+	b.enums = nil
+	b.configNames = nil
+	b.required = nil
+	b.loaded = false
+	b.flagNormalizer = nil
+	b.fileConfig = nil
+	b.configFileFound = false
+	b.embeds = nil
+	b.filePreferred = nil
+	b.remoteMergeAll = false
+	b.remoteConfig = nil
+	b.templateValues = false
+	b.configPerm = 0
+	b.lazy = false
+	b.lazyLoad = nil
+	b.lazyErr = nil
+	b.lazyDone = false
+	b.captureTo = ""
+	b.replayFrom = ""
+	b.noDurationHook = false
+	b.noWeakInput = false
+	b.namespace = ""
+	b.strictSearch = false
+	b.envBindings = nil
+	b.sliceEnvKeys = nil
+	b.defaultsErr = nil
+	b.watchAllPaths = false
+	b.configValueFlag = false
+	b.onLoad = nil
+	b.envPriorities = nil
+	b.overrides = nil
+	b.charset = ""
+	b.unusedTargets = nil
+	b.remaps = nil
+	b.streamLarge = false
+	b.sources = nil
+	b.configEnvBase64 = nil
+	b.reportMerges = nil
+	b.mergeLayers = nil
+	b.remoteLayers = nil
+	b.whenDefaults = nil
+	b.whenReplaced = nil
+	b.maxConfigSize = 0
+	b.subFiles = nil
+	b.watchStops = nil
+	b.remoteKeyTTLs = nil
+	b.envFoldCase = false
+	b.keyTypes = nil
+	b.rewritten = nil
+	b.pathKeys = nil
+	b.parsedConfig = nil
+	b.boolEnvKeys = nil
+	b.printConfigFormat = ""
+	b.structEnvKeys = nil
+	b.historyKeys = nil
+	b.historyDepth = 0
+	b.history = nil
+	b.newestDir = ""
+	b.newestPattern = ""
+	b.envAliases = nil
+	b.strictPaths = false
+	b.instanceName = ""
+	b.requireEnvRefs = false
+	b.diffStreams = nil
+	b.watchCtx = nil
+	b.argOverrides = false
+}
+
 // Name implements the glue.Bundle interface.
 func (b *Bundle) Name() string {
-	return BundleName
+	return b.tag(BundleName)
 }
 
 // Build implements the glue.Bundle interface.
 func (b *Bundle) Build(builder di.Builder) error {
-	return builder.Apply(
-		di.Provide(
+	var tags = di.Tags{{Name: b.tag(tagViper)}}
+	for _, tag := range b.provideTags {
+		tags = append(tags, di.Tag{Name: tag})
+	}
+
+	var (
+		viperDef = di.Provide(
 			b.provideViper,
-			di.Constraint(1, di.WithTags(tagViperFlagSet)),
-		),
+			di.Constraint(1, di.WithTags(b.tag(tagViperFlagSet))),
+			di.Constraint(2, di.Optional(true), di.WithTags(TagFS)),
+			di.Constraint(3, di.Optional(true), di.WithTags(TagDefaults)),
+			tags,
+		)
+		viewDef = di.Provide(
+			b.provideView,
+			di.Constraint(0, di.WithTags(b.tag(tagViper))),
+			di.As(new(Config)),
+			b.viewTags(),
+		)
+	)
+
+	if b.lazy {
+		// the view is the only lazy config surface, so it is set up instead of viper instance, which isn't loaded.
+		viperDef = di.Provide(b.provideLazyViper, tags)
+		viewDef = di.Provide(
+			b.provideLazyView,
+			di.Constraint(1, di.WithTags(b.tag(tagViperFlagSet))),
+			di.Constraint(2, di.Optional(true), di.WithTags(TagFS)),
+			di.Constraint(3, di.Optional(true), di.WithTags(TagDefaults)),
+			di.As(new(Config)),
+			b.viewTags(),
+		)
+	}
+
+	var defs = []di.BuilderOption{
+		viperDef,
 		di.Provide(b.provideFlagSet, glue.AsPersistentFlags(), di.Tags{{
-			Name: tagViperFlagSet,
+			Name: b.tag(tagViperFlagSet),
 		}}),
-	)
+		viewDef,
+	}
+
+	if len(b.printConfigFormat) > 0 {
+		defs = append(defs, di.Provide(
+			b.providePrintConfig,
+			b.viewConstraint(),
+			glue.AsPersistentPreRunner(),
+		))
+	}
+
+	return builder.Apply(defs...)
 }
 
-func (b *Bundle) provideViper(ctx context.Context, flagSet *pflag.FlagSet) (_ *viper.Viper, err error) {
-	if !b.dontUseConfigFile {
-		var path, ok = ctx.Value("app.path").(string)
-		if !ok {
-			return nil, ErrUndefinedAppPath
+func (b *Bundle) init() {
+	b.viper = viper.New()
+	b.defaults = make(map[string]interface{})
+
+	for _, option := range b.options {
+		option.apply(b)
+	}
+
+	// viper.Viper.SetEnvPrefix ignores an empty prefix, so the prefix is set once all options are applied.
+	if len(b.envPrefix) > 0 {
+		b.viper.SetEnvPrefix(b.envPrefix)
+	}
+
+	b.bindStructEnvs()
+	b.bindEnvAliases()
+
+	if b.automaticEnv && b.envSnapshot == nil && len(b.replayFrom) == 0 {
+		b.viper.AutomaticEnv()
+	}
+}
+
+func (b *Bundle) provideViper(
+	ctx context.Context,
+	flagSet *pflag.FlagSet,
+	fsys fs.FS,
+	defaults []Defaults,
+) (_ *viper.Viper, _ func() error, err error) {
+	if b.defaultsErr != nil {
+		return nil, nil, b.defaultsErr
+	}
+
+	b.fsys = fsys
+	b.applyProvidedDefaults(defaults)
+
+	// closer stops config watching on container close, e.g. on glue kernel shutdown.
+	var closer = func() error {
+		b.stopWatch()
+		return nil
+	}
+
+	if len(b.replayFrom) > 0 {
+		if err = b.replay(); err != nil {
+			return nil, nil, err
 		}
 
-		b.viper.AddConfigPath(path)
+		return b.viper, closer, nil
+	}
+
+	if err = b.bindFlags(); err != nil {
+		return nil, nil, err
+	}
+
+	if b.lazy {
+		b.lazyLoad = func() error { return b.load(ctx, flagSet) }
+		return b.viper, closer, nil
+	}
+
+	if err = b.load(ctx, flagSet); err != nil {
+		return nil, nil, err
+	}
+
+	return b.viper, closer, nil
+}
+
+// load reads config and applies post-processing.
+func (b *Bundle) load(ctx context.Context, flagSet *pflag.FlagSet) (err error) {
+	if !b.dontUseConfigFile {
+		if b.fsys == nil {
+			var path, ok = ctx.Value("app.path").(string)
+			if !ok {
+				return ErrUndefinedAppPath
+			}
+
+			b.configPaths = append(b.configPaths, path)
+			b.viper.AddConfigPath(path)
+		}
 
 		var configFile string
-		if configFile, err = flagSet.GetString("config"); err != nil {
-			return nil, fmt.Errorf("unable to get config flag value : %w", err)
+		if configFile, err = flagSet.GetString(b.flagName("config")); err != nil {
+			return fmt.Errorf("unable to get config flag value : %w", err)
 		}
 
+		var notFound error
 		if len(configFile) > 0 {
 			b.viper.SetConfigFile(configFile)
+		} else if len(b.newestPattern) > 0 {
+			var path string
+			if path, err = b.findNewest(); err != nil {
+				return err
+			}
+
+			if len(path) > 0 {
+				b.useNewest(path)
+			} else {
+				notFound = b.newNewestNotFoundError()
+			}
 		}
 
-		err = b.viper.ReadInConfig()
-		if err != nil {
-			return nil, fmt.Errorf("unable to read config file : '%s' : %w",
+		if b.strictPaths {
+			if err = b.checkPaths(); err != nil {
+				return err
+			}
+		}
+
+		if b.strictSearch && notFound == nil {
+			if err = b.checkAmbiguous(); err != nil {
+				return err
+			}
+		}
+
+		if err = notFound; err == nil {
+			err = b.readInConfig()
+		}
+
+		switch {
+		case err == nil:
+			b.configFileFound = true
+		case errors.As(err, &viper.ConfigFileNotFoundError{}) && b.hasNonFileSource():
+			err = nil
+		default:
+			return fmt.Errorf("unable to read config file : '%s' : %w",
 				configFile, err)
 		}
 	}
 
-	return b.viper, nil
+	if len(b.remoteProviders) > 0 {
+		if err = b.readRemoteConfig(ctx); err != nil {
+			return err
+		}
+	}
+
+	if err = b.afterRead(); err != nil {
+		return fmt.Errorf("unable to process config : %w", err)
+	}
+
+	b.applyFilePreferred()
+
+	for _, def := range b.defaultFuncs {
+		if !b.viper.IsSet(def.key) {
+			b.setDefault(def.key, def.fn())
+		}
+	}
+
+	b.applyConditionalDefaults()
+
+	if b.automaticEnv && b.envSnapshot != nil {
+		b.applyEnvSnapshot()
+	}
+
+	if b.automaticEnv && b.envFoldCase {
+		b.applyFoldedEnvs()
+	}
+
+	b.applyEnvPriorities()
+	b.applyRemaps()
+
+	if len(b.sliceEnvKeys) > 0 {
+		b.applySliceEnvs()
+	}
+
+	if len(b.boolEnvKeys) > 0 {
+		b.applyBoolEnvs()
+	}
+
+	if b.configValueFlag {
+		if err = b.applyConfigValues(flagSet); err != nil {
+			return err
+		}
+	}
+
+	if b.argOverrides {
+		b.applyPositionalOverrides(flagSet)
+	}
+
+	if b.templateValues {
+		if err = b.renderTemplates(); err != nil {
+			return fmt.Errorf("unable to render config templates : %w", err)
+		}
+	}
+
+	if len(b.keyTypes) > 0 {
+		if err = b.applyKeyTypes(); err != nil {
+			return err
+		}
+	}
+
+	if len(b.pathKeys) > 0 {
+		if err = b.resolvePathKeys(); err != nil {
+			return err
+		}
+	}
+
+	if err = b.validate(); err != nil {
+		return err
+	}
+
+	b.warnUnusedKeys()
+
+	for _, overrides := range b.overrides {
+		for key, value := range overrides {
+			b.viper.Set(key, value)
+		}
+	}
+
+	if len(b.captureTo) > 0 {
+		if err = b.capture(); err != nil {
+			return err
+		}
+	}
+
+	if err = b.runOnLoad(); err != nil {
+		return err
+	}
+
+	b.recordHistory()
+
+	if b.configFileFound && b.fsys == nil {
+		b.watchCtx = ctx
+		if b.watchEnabled() {
+			b.watch(ctx)
+		}
+	}
+
+	b.loaded = true
+
+	return nil
+}
+
+// validate checks env variables, required keys, env references and enum keys of loaded config.
+func (b *Bundle) validate() (err error) {
+	if b.envStrict {
+		if err = b.validateEnv(); err != nil {
+			return err
+		}
+	}
+
+	if err = b.checkRequired(); err != nil {
+		return err
+	}
+
+	if b.requireEnvRefs {
+		if err = b.checkEnvRefs(); err != nil {
+			return err
+		}
+	}
+
+	for _, enum := range b.enums {
+		if _, err = b.getEnum(b.nsKey(enum.key), enum.allowed...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b *Bundle) readInConfig() (err error) {
+	if b.maxConfigSize > 0 {
+		if err = b.checkConfigSize(); err != nil {
+			return err
+		}
+	}
+
+	b.parsedConfig = nil
+
+	if b.fsys != nil {
+		err = b.readInConfigFromFS()
+	} else if path := b.configFilePath(); len(path) > 0 && b.isParsed(path) {
+		// the config file is decoded once, its settings are passed to viper by afterRead.
+		if b.parsedConfig, err = b.readFile(path); err == nil {
+			b.viper.SetConfigFile(path)
+		}
+	} else {
+		err = b.readInConfigByNames()
+	}
+
+	if errors.Is(err, os.ErrPermission) {
+		return fmt.Errorf("config file %q is not readable: permission denied : %w", b.configFilePath(), err)
+	}
+
+	if errors.As(err, &viper.ConfigFileNotFoundError{}) {
+		return b.newConfigFileNotFoundError(err)
+	}
+
+	if errors.As(err, &viper.ConfigParseError{}) && b.looksLikeYAML() {
+		return fmt.Errorf("config file %q is not valid JSON, it looks like YAML content : %w",
+			b.configFilePath(), err)
+	}
+
+	return err
+}
+
+// configFilePath returns path of the config file to be read, the set config file or the file found in search
+// paths, so the found file is read by bundle instead of viper when it has to be parsed by bundle.
+func (b *Bundle) configFilePath() string {
+	if path := b.viper.ConfigFileUsed(); len(path) > 0 {
+		return path
+	}
+
+	return b.findConfigFile()
+}
+
+// readInConfigByNames reads config file trying configured names in order until one is found.
+func (b *Bundle) readInConfigByNames() (err error) {
+	if len(b.configNames) == 0 || len(b.viper.ConfigFileUsed()) > 0 {
+		return b.viper.ReadInConfig()
+	}
+
+	for _, name := range b.configNames {
+		b.viper.SetConfigName(name)
+		if err = b.viper.ReadInConfig(); !errors.As(err, &viper.ConfigFileNotFoundError{}) {
+			return err
+		}
+	}
+
+	return err
+}
+
+// afterRead post-processes config read from file and merges additional config sources,
+// it is called on initial read and on every reload.
+func (b *Bundle) afterRead() (err error) {
+	var modified bool
+	b.config = make(map[string]interface{})
+	b.mergeLayers = nil
+
+	if !b.dontUseConfigFile && b.configFileFound {
+		var path = b.viper.ConfigFileUsed()
+		if b.fsys == nil {
+			if path, err = filepath.Abs(path); err != nil {
+				return fmt.Errorf("unable to resolve config file path : %w", err)
+			}
+		}
+
+		if b.parsedConfig != nil {
+			b.config, b.parsedConfig = b.parsedConfig, nil
+			modified = true
+		} else {
+			modified = b.isParsed(path)
+
+			if b.config, err = b.readFile(path); err != nil {
+				return err
+			}
+		}
+
+		if b.includes {
+			var visited = map[string]bool{path: true}
+			if b.config, err = b.resolveIncludes(b.config, filepath.Dir(path), visited, 0); err != nil {
+				return err
+			}
+
+			modified = true
+		}
+
+		b.addMergeLayer(layerFile, path, b.config)
+	}
+
+	// config file settings are copied only if other config sources are merged into them in place.
+	b.fileConfig = b.config
+	if len(b.subFiles)+len(b.sources)+len(b.splitKeyDirs)+len(b.configEnvJSON) > 0 {
+		b.fileConfig = copySettings(b.config)
+	}
+
+	if b.remoteConfig != nil {
+		b.config = deepMerge(copySettings(b.remoteConfig), b.config)
+		modified = true
+	}
+
+	if len(b.configEnvBase64) > 0 {
+		var base map[string]interface{}
+		if base, err = b.readEnvBase64(); err != nil {
+			return err
+		}
+
+		b.config = deepMerge(base, b.config)
+		modified = true
+	}
+
+	if len(b.embeds) > 0 {
+		var base map[string]interface{}
+		if base, err = b.readEmbeds(); err != nil {
+			return err
+		}
+
+		b.config = deepMerge(base, b.config)
+		modified = true
+	}
+
+	if len(b.subFiles) > 0 {
+		var settings map[string]interface{}
+		if settings, err = b.readSubFiles(); err != nil {
+			return err
+		}
+
+		b.config = deepMerge(b.config, settings)
+		modified = true
+	}
+
+	if len(b.sources) > 0 {
+		var settings map[string]interface{}
+		if settings, err = b.readSources(); err != nil {
+			return err
+		}
+
+		b.config = deepMerge(b.config, settings)
+		modified = true
+	}
+
+	for _, dir := range b.splitKeyDirs {
+		var settings map[string]interface{}
+		if settings, err = readSplitKeyDir(dir); err != nil {
+			return err
+		}
+
+		b.addMergeLayer(layerSplitKey, dir, settings)
+
+		b.config = deepMerge(b.config, settings)
+		modified = true
+	}
+
+	for _, name := range b.configEnvJSON {
+		var value, ok = b.lookupEnv(name)
+		if !ok {
+			continue
+		}
+
+		var settings map[string]interface{}
+		if err = json.Unmarshal([]byte(value), &settings); err != nil {
+			return fmt.Errorf("unable to parse env '%s' as JSON : %w", name, err)
+		}
+
+		b.addMergeLayer(layerEnvJSON, "env:"+name, settings)
+
+		b.config = deepMerge(b.config, lowercaseKeys(settings))
+		modified = true
+	}
+
+	if b.normalizeKeys {
+		b.config = lowercaseKeys(b.config)
+		modified = true
+	}
+
+	b.reportMergeLayers()
+
+	if !modified {
+		return nil
+	}
+
+	return b.replaceConfig(b.config)
+}
+
+// applyConditionalDefaults sets conditional default values of keys which are unset or defaulted statically.
+// Previously applied conditional defaults are reverted to static defaults first, so conditions are
+// re-evaluated against reloaded config.
+func (b *Bundle) applyConditionalDefaults() {
+	for key, value := range b.whenReplaced {
+		if value == nil {
+			delete(b.defaults, key)
+		}
+
+		// viper has no default removal, the nil default is treated as unset.
+		b.viper.SetDefault(key, value)
+	}
+
+	b.whenReplaced = make(map[string]interface{}, len(b.whenDefaults))
+	for _, def := range b.whenDefaults {
+		if _, ok := b.whenReplaced[def.key]; ok || (b.viper.IsSet(def.key) && b.source(def.key) != SourceDefault) {
+			continue
+		}
+
+		if def.when(b.viper) {
+			b.whenReplaced[def.key] = b.defaults[def.key]
+			b.setDefault(def.key, def.value)
+		}
+	}
+}
+
+func (b *Bundle) bindFlags() (err error) {
+	for _, flagSet := range b.flagSets {
+		flagSet.Visit(func(flag *pflag.Flag) {
+			if err != nil {
+				return
+			}
+
+			if err = b.viper.BindPFlag(b.flagKey(flag.Name), flag); err != nil {
+				err = fmt.Errorf("unable to bind flag '%s' : %w", flag.Name, err)
+			}
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// flagKey returns viper key of flag name.
+func (b *Bundle) flagKey(name string) string {
+	if b.flagNormalizer != nil {
+		return b.flagNormalizer(name)
+	}
+
+	return strings.ReplaceAll(name, "-", keyDelimiter)
+}
+
+// flagChanged reports whether flag bound to key is changed.
+func (b *Bundle) flagChanged(key string) (changed bool) {
+	for _, flagSet := range b.flagSets {
+		flagSet.Visit(func(flag *pflag.Flag) {
+			changed = changed || strings.EqualFold(b.flagKey(flag.Name), key)
+		})
+	}
+
+	return changed
+}
+
+// FlagSet returns the flag set the bundle registers, it is nil until the flag set is provided.
+func (b *Bundle) FlagSet() *pflag.FlagSet {
+	return b.flagSet
 }
 
 func (b *Bundle) provideFlagSet() (*pflag.FlagSet, error) {
-	var flagSet = pflag.NewFlagSet(BundleName, pflag.ContinueOnError)
+	var flagSet = pflag.NewFlagSet(b.Name(), pflag.ContinueOnError)
+	b.flagSet = flagSet
 
 	if !b.dontUseConfigFile {
-		flagSet.StringP("config", "c", "", "config file")
+		if len(b.instanceName) == 0 {
+			flagSet.StringP("config", "c", "", "config file")
+		} else {
+			flagSet.String(b.flagName("config"), "", b.instanceName+" config file")
+		}
+	}
+
+	if b.configValueFlag {
+		flagSet.StringArray(b.flagName(configValueFlag), nil, "config value override in key:type=value format, "+
+			"type is one of int, bool, float, duration or string")
+	}
+
+	if len(b.printConfigFormat) > 0 {
+		flagSet.Bool(b.flagName(printConfigFlag), false, "print effective config and exit")
 	}
 
+	// flags of the surrounding CLI are unknown to the flag set, they are ignored, so bundle flags
+	// in short and long forms are extracted from the full arg set.
 	flagSet.ParseErrorsWhitelist.UnknownFlags = true
 
 	var err = flagSet.Parse(os.Args)
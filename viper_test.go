@@ -0,0 +1,468 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/gozix/di"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+// configDir creates temp dir with files of name to content map.
+func configDir(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	var dir = t.TempDir()
+	for name, content := range files {
+		writeFile(t, filepath.Join(dir, name), content)
+	}
+
+	return dir
+}
+
+// writeFile writes content to the file creating parent directories.
+func writeFile(t *testing.T, name string, content string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(name), 0o755))
+	require.NoError(t, os.WriteFile(name, []byte(content), 0o644))
+}
+
+// replaceFile atomically replaces file content, so watchers never observe partially written file.
+func replaceFile(t *testing.T, name string, content string) {
+	t.Helper()
+
+	var tmp = name + ".tmp"
+	writeFile(t, tmp, content)
+	require.NoError(t, os.Rename(tmp, name))
+}
+
+// setArgs replaces command line args parsed by the bundle flag set until the test ends.
+func setArgs(t *testing.T, args ...string) {
+	t.Helper()
+
+	var orig = os.Args
+	os.Args = append([]string{"app"}, args...)
+
+	t.Cleanup(func() {
+		os.Args = orig
+	})
+}
+
+// chdir changes working directory to dir until the test ends.
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+
+	var wd, err = os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+
+	t.Cleanup(func() {
+		require.NoError(t, os.Chdir(wd))
+	})
+}
+
+// provide provides viper of the bundle with path as application path and args as command line args,
+// watching is stopped once the test ends.
+func provide(t *testing.T, b *Bundle, path string, args ...string) (*viper.Viper, error) {
+	t.Helper()
+	setArgs(t, args...)
+
+	var flagSet, err = b.provideFlagSet()
+	require.NoError(t, err)
+
+	var (
+		ctx    = context.WithValue(context.Background(), "app.path", path)
+		v      *viper.Viper
+		closer func() error
+	)
+
+	if v, closer, err = b.provideViper(ctx, flagSet, nil, nil); closer != nil {
+		t.Cleanup(func() {
+			_ = closer()
+		})
+	}
+
+	return v, err
+}
+
+// buildContainer builds DI container of bundles with path as application path, it is closed once the test ends.
+func buildContainer(t *testing.T, path string, bundles ...*Bundle) di.Container {
+	t.Helper()
+	setArgs(t)
+
+	var builder, err = di.NewBuilder(di.Provide(func() context.Context {
+		return context.WithValue(context.Background(), "app.path", path)
+	}))
+
+	require.NoError(t, err)
+
+	for _, b := range bundles {
+		require.NoError(t, b.Build(builder))
+	}
+
+	var ctn di.Container
+	ctn, err = builder.Build()
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = ctn.Close()
+	})
+
+	return ctn
+}
+
+func TestBundle_Name(t *testing.T) {
+	require.Equal(t, BundleName, NewBundle().Name())
+}
+
+func TestBundle_Provide(t *testing.T) {
+	var dir = configDir(t, map[string]string{
+		"config.json": `{"a":{"b":"c"}}`,
+	})
+
+	var v, err = provide(t, NewBundle(), dir)
+	require.NoError(t, err)
+	require.Equal(t, "c", v.GetString("a.b"))
+	require.Equal(t, filepath.Join(dir, "config.json"), v.ConfigFileUsed())
+}
+
+// deniedFS is file system denying reads of files without read permission bits, the way OS does it
+// for non-root users, so permission errors are tested regardless of the user running tests.
+type deniedFS fstest.MapFS
+
+// Open implements fs.FS.
+func (f deniedFS) Open(name string) (fs.File, error) {
+	if file, ok := f[name]; ok && file.Mode.Perm()&0o444 == 0 {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrPermission}
+	}
+
+	return fstest.MapFS(f).Open(name)
+}
+
+// Stat implements fs.StatFS.
+func (f deniedFS) Stat(name string) (fs.FileInfo, error) {
+	return fstest.MapFS(f).Stat(name)
+}
+
+func TestBundle_PermissionDenied(t *testing.T) {
+	setArgs(t)
+
+	var (
+		b    = NewBundle(ConfigPath("conf"))
+		fsys = deniedFS{"conf/config.json": {Data: []byte(`{"a":1}`), Mode: 0o200}}
+	)
+
+	var flagSet, err = b.provideFlagSet()
+	require.NoError(t, err)
+
+	_, _, err = b.provideViper(context.Background(), flagSet, fsys, nil)
+	require.Error(t, err)
+	require.ErrorIs(t, err, os.ErrPermission)
+	require.Contains(t, err.Error(), `config file "conf/config.json" is not readable: permission denied`)
+}
+
+func TestBundle_DefaultFunc(t *testing.T) {
+	var (
+		calls int
+		cpus  = func() interface{} {
+			calls++
+			return runtime.NumCPU()
+		}
+	)
+
+	var b = NewBundle(DefaultFunc("workers", cpus))
+	require.Equal(t, 0, calls, "default func must not be called on construction")
+
+	var v, err = provide(t, b, configDir(t, map[string]string{
+		"config.json": `{}`,
+	}))
+
+	require.NoError(t, err)
+	require.Equal(t, runtime.NumCPU(), v.GetInt("workers"))
+	require.Equal(t, 1, calls)
+
+	calls = 0
+	v, err = provide(t, NewBundle(DefaultFunc("workers", cpus)), configDir(t, map[string]string{
+		"config.json": `{"workers": 3}`,
+	}))
+
+	require.NoError(t, err)
+	require.Equal(t, 3, v.GetInt("workers"))
+	require.Equal(t, 0, calls, "default func must not be called when key is set")
+}
+
+func TestBundle_Reset(t *testing.T) {
+	var (
+		dir = configDir(t, map[string]string{"config.json": `{"name": "file"}`})
+		b   = NewBundle(Default("port", 8080), OnChange(func(fsnotify.Event) {}))
+	)
+
+	var v, err = provide(t, b, dir)
+	require.NoError(t, err)
+	require.NotNil(t, b.watchDone)
+
+	var (
+		done    = b.watchDone
+		flagSet = b.FlagSet()
+	)
+
+	v.Set("port", 9090)
+	require.NoError(t, v.MergeConfigMap(map[string]interface{}{"extra": true}))
+
+	b.Reset()
+
+	require.NotSame(t, v, b.viper)
+	require.Same(t, flagSet, b.FlagSet(), "provided flag set must be kept")
+
+	select {
+	case <-done:
+	default:
+		t.Fatal("watching must be stopped")
+	}
+
+	require.Equal(t, 8080, b.viper.GetInt("port"))
+	require.False(t, b.viper.IsSet("extra"))
+	require.False(t, b.viper.IsSet("name"), "config must be provided again to be read")
+
+	v, err = provide(t, b, dir)
+	require.NoError(t, err)
+	require.Equal(t, "file", v.GetString("name"))
+	require.Equal(t, 8080, v.GetInt("port"))
+}
+
+func TestBundle_ProvideTag(t *testing.T) {
+	var (
+		dir = configDir(t, map[string]string{"config.json": `{"name": "app"}`})
+		b   = NewBundle(ProvideTag("app.config"))
+		ctn = buildContainer(t, dir, b)
+	)
+
+	var tagged *viper.Viper
+	require.NoError(t, ctn.Resolve(&tagged, di.WithTags("app.config")))
+	require.Equal(t, "app", tagged.GetString("name"))
+
+	var untagged *viper.Viper
+	require.NoError(t, ctn.Resolve(&untagged))
+	require.Same(t, tagged, untagged)
+}
+
+func TestBundle_ConfigEnvJSON(t *testing.T) {
+	t.Setenv("APP_CONFIG_JSON", `{"db": {"host": "env"}, "Debug": true}`)
+
+	var dir = configDir(t, map[string]string{
+		"config.json": `{"db": {"host": "file", "port": 5432}, "debug": false}`,
+	})
+
+	var v, err = provide(t, NewBundle(ConfigEnvJSON("APP_CONFIG_JSON")), dir)
+	require.NoError(t, err)
+	require.Equal(t, "env", v.GetString("db.host"))
+	require.Equal(t, 5432, v.GetInt("db.port"))
+	require.True(t, v.GetBool("debug"))
+}
+
+func TestBundle_ConfigEnvJSONMalformed(t *testing.T) {
+	t.Setenv("APP_CONFIG_JSON", `{"db":`)
+
+	var _, err = provide(t, NewBundle(ConfigEnvJSON("APP_CONFIG_JSON")), configDir(t, map[string]string{
+		"config.json": `{}`,
+	}))
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unable to parse env 'APP_CONFIG_JSON' as JSON")
+}
+
+func TestBundle_ConfigNames(t *testing.T) {
+	var dir = configDir(t, map[string]string{
+		"app.json":      `{"name": "app"}`,
+		"settings.json": `{"name": "settings"}`,
+	})
+
+	var v, err = provide(t, NewBundle(ConfigNames("config", "app", "settings")), dir)
+	require.NoError(t, err)
+	require.Equal(t, "app", v.GetString("name"))
+	require.Equal(t, filepath.Join(dir, "app.json"), v.ConfigFileUsed())
+
+	_, err = provide(t, NewBundle(ConfigNames("config", "missing")), dir)
+
+	var notFound *ConfigFileNotFoundError
+	require.ErrorAs(t, err, &notFound)
+	require.Equal(t, "config, missing", notFound.Name)
+	require.Contains(t, notFound.Patterns, "missing.json")
+}
+
+func TestBundle_FlagNormalizer(t *testing.T) {
+	var flagSet = pflag.NewFlagSet("app", pflag.ContinueOnError)
+	flagSet.String("db-host", "", "database host")
+	flagSet.String("log-level", "", "log level")
+	require.NoError(t, flagSet.Parse([]string{"--db-host=localhost", "--log-level=debug"}))
+
+	var v, err = provide(t, NewBundle(DontUseConfigFile(), BindFlags(flagSet)), t.TempDir())
+	require.NoError(t, err)
+	require.Equal(t, "localhost", v.GetString("db.host"))
+	require.Equal(t, "debug", v.GetString("log.level"))
+
+	var b = NewBundle(DontUseConfigFile(), BindFlags(flagSet), FlagNormalizer(func(name string) string {
+		return strings.ReplaceAll(name, "-", "_")
+	}))
+
+	v, err = provide(t, b, t.TempDir())
+	require.NoError(t, err)
+	require.Equal(t, "localhost", v.GetString("db_host"))
+	require.False(t, v.IsSet("db.host"))
+}
+
+func TestBundle_Env(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://env")
+
+	var b = NewBundle(
+		DontUseConfigFile(),
+		Env("db.url", "DATABASE_URL", "postgres://default"),
+		Env("db.pool", "DATABASE_POOL", 10),
+	)
+
+	var v, err = provide(t, b, t.TempDir())
+	require.NoError(t, err)
+	require.Equal(t, "postgres://env", v.GetString("db.url"))
+	require.Equal(t, 10, v.GetInt("db.pool"))
+}
+
+func TestBundle_FlagSet(t *testing.T) {
+	var (
+		dir = configDir(t, map[string]string{"config.json": `{}`})
+		b   = NewBundle()
+	)
+
+	require.Nil(t, b.FlagSet())
+
+	var (
+		ctn     = buildContainer(t, dir, b)
+		flagSet *pflag.FlagSet
+	)
+
+	require.NoError(t, ctn.Resolve(&flagSet, di.WithTags(TagFlagSet)))
+	require.Same(t, b.FlagSet(), flagSet)
+	require.NotNil(t, flagSet.Lookup("config"))
+	require.NotNil(t, flagSet.ShorthandLookup("c"))
+}
+
+func TestBundle_EnvPrefixEmpty(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://bare")
+	t.Setenv("ENV_DATABASE_URL", "postgres://prefixed")
+
+	var v, err = provide(t, NewBundle(DontUseConfigFile(), EnvPrefix("")), t.TempDir())
+	require.NoError(t, err)
+	require.Equal(t, "postgres://bare", v.GetString("database.url"))
+
+	v, err = provide(t, NewBundle(DontUseConfigFile()), t.TempDir())
+	require.NoError(t, err)
+	require.Equal(t, "postgres://prefixed", v.GetString("database.url"))
+
+	v, err = provide(t, NewBundle(DontUseConfigFile(), EnvPrefix(""), EnvPrefix("ENV")), t.TempDir())
+	require.NoError(t, err)
+	require.Equal(t, "postgres://prefixed", v.GetString("database.url"), "the last option must win")
+}
+
+func TestNewBundleWithDefaults(t *testing.T) {
+	var (
+		defaults = []byte("db:\n  host: localhost\n  port: 5432\nname: default\n")
+		dir      = configDir(t, map[string]string{"config.json": `{"db": {"port": 6432}}`})
+	)
+
+	var v, err = provide(t, NewBundleWithDefaults(defaults, "yaml", Default("name", "option")), dir)
+	require.NoError(t, err)
+	require.Equal(t, "localhost", v.GetString("db.host"), "embedded defaults must fill gaps")
+	require.Equal(t, 6432, v.GetInt("db.port"), "config file must take precedence")
+	require.Equal(t, "option", v.GetString("name"), "Default option must take precedence")
+
+	_, err = provide(t, NewBundleWithDefaults([]byte("db: [unclosed"), "yaml"), dir)
+	require.ErrorContains(t, err, "unable to read defaults")
+}
+
+func TestBundle_ConditionalDefault(t *testing.T) {
+	var isProd = func(v *viper.Viper) bool {
+		return v.GetString("mode") == "prod"
+	}
+
+	var tests = []struct {
+		name    string
+		content string
+		level   string
+	}{
+		{name: "prod", content: `{"mode": "prod"}`, level: "info"},
+		{name: "dev", content: `{"mode": "dev"}`, level: "debug"},
+		{name: "set", content: `{"mode": "prod", "log": {"level": "warn"}}`, level: "warn"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b = NewBundle(
+				Default("log.level", "debug"),
+				ConditionalDefault("log.level", isProd, "info"),
+				ConditionalDefault("log.level", isProd, "error"),
+			)
+
+			var v, err = provide(t, b, configDir(t, map[string]string{"config.json": tt.content}))
+			require.NoError(t, err)
+			require.Equal(t, tt.level, v.GetString("log.level"))
+		})
+	}
+}
+
+func TestBundle_EnvOnly(t *testing.T) {
+	t.Setenv("ENV_DB_HOST", "env")
+
+	var dir = configDir(t, map[string]string{"config.json": `{"db": {"host": "file", "name": "file"}}`})
+
+	var b = NewBundle(EnvOnly(), Default("db.port", 5432))
+
+	var v, err = provide(t, b, dir)
+	require.NoError(t, err)
+	require.Equal(t, "env", v.GetString("db.host"))
+	require.Equal(t, 5432, v.GetInt("db.port"))
+	require.False(t, v.IsSet("db.name"), "config file must not be read")
+	require.Empty(t, v.ConfigFileUsed())
+	require.Nil(t, b.FlagSet().Lookup("config"), "config flag must not be registered")
+
+	require.NoError(t, b.Set("db.name", "override"))
+	require.Equal(t, "override", v.GetString("db.name"))
+}
+
+func TestBundle_ConfigFlagUnknownFlags(t *testing.T) {
+	var (
+		dir  = configDir(t, map[string]string{"config.json": `{"name": "search"}`, "custom.json": `{"name": "custom"}`})
+		file = filepath.Join(dir, "custom.json")
+	)
+
+	var tests = []struct {
+		name string
+		args []string
+	}{
+		{name: "long", args: []string{"serve", "--verbose", "--config", file, "--port=8080"}},
+		{name: "long with value", args: []string{"--log-level=debug", "--config=" + file, "-v"}},
+		{name: "short", args: []string{"-x", "-c", file, "--unknown"}},
+		{name: "short attached", args: []string{"--unknown=1", "-c" + file}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v, err = provide(t, NewBundle(), dir, tt.args...)
+			require.NoError(t, err)
+			require.Equal(t, "custom", v.GetString("name"))
+			require.Equal(t, file, v.ConfigFileUsed())
+		})
+	}
+}
@@ -0,0 +1,473 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// fileWatchDebounce is default debounce window of WatchFile callbacks.
+const fileWatchDebounce = 100 * time.Millisecond
+
+// watchEnabled reports whether any change handler is registered.
+func (b *Bundle) watchEnabled() bool {
+	return len(b.onChange) > 0 || len(b.onDiff) > 0 || len(b.onLoad) > 0 || len(b.diffStreams) > 0
+}
+
+// watch starts watching the config file and dispatches its events to the change handlers.
+// Watching stops once ctx is done or the bundle closer is called.
+func (b *Bundle) watch(ctx context.Context) {
+	b.settings = b.viper.AllSettings()
+	ctx, b.watchCancel = context.WithCancel(ctx)
+
+	b.watchSubFiles()
+
+	var handler = func(in fsnotify.Event) {
+		if ctx.Err() != nil {
+			return
+		}
+
+		b.handleChange(in, func() bool {
+			if b.watchAllPaths {
+				return b.reselectConfigFile(in)
+			}
+
+			if err := b.readInConfig(); err != nil {
+				log.Printf("error reading config file: %v\n", err)
+				return false
+			}
+
+			return true
+		})
+	}
+
+	if b.watchDebounce > 0 {
+		handler, b.debounceDone = debounce(ctx, b.watchDebounce, handler)
+	}
+
+	var err error
+	if b.watchAllPaths {
+		if b.watchDone, err = b.watchPaths(ctx, handler); err != nil {
+			log.Printf("error watching config paths: %v\n", err)
+		}
+
+		return
+	}
+
+	// viper.Viper.WatchConfig can't be stopped, so the config file is watched by the bundle.
+	if b.watchDone, err = b.watchConfigFile(ctx, handler); err != nil {
+		log.Printf("error watching config file: %v\n", err)
+	}
+}
+
+// watchConfigFile watches directory of the config file and dispatches events of the config file to handler.
+// Like viper does, the config file symlink target change is dispatched as well, e.g. of kubernetes ConfigMap.
+func (b *Bundle) watchConfigFile(ctx context.Context, handler func(in fsnotify.Event)) (<-chan struct{}, error) {
+	var file, err = filepath.Abs(b.viper.ConfigFileUsed())
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve config file path : %w", err)
+	}
+
+	var watcher *fsnotify.Watcher
+	if watcher, err = fsnotify.NewWatcher(); err != nil {
+		return nil, fmt.Errorf("unable to create file watcher : %w", err)
+	}
+
+	if err = watcher.Add(filepath.Dir(file)); err != nil {
+		_ = watcher.Close()
+		return nil, fmt.Errorf("unable to watch config file : '%s' : %w", file, err)
+	}
+
+	var target, _ = filepath.EvalSymlinks(file)
+
+	return runWatcher(ctx, watcher, func(event fsnotify.Event) bool {
+		var current, _ = filepath.EvalSymlinks(file)
+		if len(current) > 0 && current != target {
+			target = current
+			return true
+		}
+
+		return filepath.Clean(event.Name) == file && event.Op&(fsnotify.Write|fsnotify.Create) != 0
+	}, handler), nil
+}
+
+// runWatcher dispatches watcher events accepted by filter to handler until ctx is done, the watcher
+// is closed on exit. The returned channel is closed once the watcher goroutine exits.
+func runWatcher(
+	ctx context.Context,
+	watcher *fsnotify.Watcher,
+	filter func(event fsnotify.Event) bool,
+	handler func(in fsnotify.Event),
+) <-chan struct{} {
+	var done = make(chan struct{})
+
+	go func() {
+		defer close(done)
+		defer watcher.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+
+				if filter(event) {
+					handler(event)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+
+				log.Printf("error watching config: %v\n", err)
+			}
+		}
+	}()
+
+	return done
+}
+
+// WatchFile watches the file independently of the config file and calls fn after the file is written.
+// Events are debounced with WatchDebounce window, 100ms by default. The parent directory is watched,
+// so the file replacing by rename is detected as well. The returned stop function stops watching and waits
+// for the fn call in progress, so it must not be called from fn.
+func (b *Bundle) WatchFile(path string, fn func()) (stop func(), err error) {
+	if path, err = filepath.Abs(path); err != nil {
+		return nil, fmt.Errorf("unable to resolve watched file path : %w", err)
+	}
+
+	var watcher *fsnotify.Watcher
+	if watcher, err = fsnotify.NewWatcher(); err != nil {
+		return nil, fmt.Errorf("unable to create file watcher : %w", err)
+	}
+
+	if err = watcher.Add(filepath.Dir(path)); err != nil {
+		_ = watcher.Close()
+		return nil, fmt.Errorf("unable to watch file : '%s' : %w", path, err)
+	}
+
+	var d = b.watchDebounce
+	if d <= 0 {
+		d = fileWatchDebounce
+	}
+
+	var (
+		ctx, cancel        = context.WithCancel(context.Background())
+		handler, debounced = debounce(ctx, d, func(fsnotify.Event) { fn() })
+		done               = make(chan struct{})
+	)
+
+	go func() {
+		defer close(done)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+
+				if filepath.Clean(event.Name) == path && event.Op&(fsnotify.Write|fsnotify.Create) != 0 {
+					handler(event)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+
+				log.Printf("error watching file '%s': %v\n", path, err)
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		_ = watcher.Close()
+		<-done
+		<-debounced
+	}, nil
+}
+
+// stopWatch stops dispatching of config file events and waits for the watcher and debounce goroutines
+// to exit, so a reload in progress is finished before diff streams are closed. It must not be called
+// from change handlers.
+func (b *Bundle) stopWatch() {
+	if b.watchCancel != nil {
+		b.watchCancel()
+		b.watchCancel = nil
+	}
+
+	if b.watchDone != nil {
+		<-b.watchDone
+		b.watchDone = nil
+	}
+
+	if b.debounceDone != nil {
+		<-b.debounceDone
+		b.debounceDone = nil
+	}
+
+	for _, stop := range b.watchStops {
+		stop()
+	}
+
+	b.watchStops = nil
+	b.watchCtx = nil
+	b.closeDiffStreams()
+}
+
+// handleChange re-reads config by read, if it isn't nil, post-processes it and calls registered change handlers.
+// Config is re-read and post-processed under the bundle lock, so readers don't see partially reloaded config.
+// The last good config is kept if re-read config fails post-processing or validation.
+func (b *Bundle) handleChange(in fsnotify.Event, read func() bool) {
+	b.mu.Lock()
+	var config, fileConfig = b.config, b.fileConfig
+	if read != nil && !read() {
+		b.mu.Unlock()
+		return
+	}
+
+	if !b.reprocess() {
+		b.restore(config, fileConfig)
+		b.mu.Unlock()
+		return
+	}
+	b.mu.Unlock()
+
+	if err := b.runOnLoad(); err != nil {
+		log.Printf("error reloading config, last good config is kept: %v\n", err)
+
+		b.mu.Lock()
+		b.restore(config, fileConfig)
+		b.mu.Unlock()
+
+		return
+	}
+
+	b.mu.Lock()
+	var settings, previous = b.viper.AllSettings(), b.settings
+	b.settings = settings
+	b.recordHistory()
+
+	var changes []KeyChange
+	if len(b.onDiff) > 0 || len(b.diffStreams) > 0 {
+		changes = b.Diff(previous, settings)
+		b.publishDiff(changes)
+	}
+	b.mu.Unlock()
+
+	for _, handler := range b.onDiff {
+		handler(changes)
+	}
+
+	for _, handler := range b.onChange {
+		handler(in)
+	}
+}
+
+// reprocess post-processes and validates re-read config and reports whether it succeeded, errors are logged.
+func (b *Bundle) reprocess() bool {
+	if err := b.afterRead(); err != nil {
+		log.Printf("error processing config file: %v\n", err)
+		return false
+	}
+
+	b.applyFilePreferred()
+
+	if len(b.whenDefaults) > 0 {
+		b.applyConditionalDefaults()
+	}
+
+	if len(b.remaps) > 0 {
+		b.applyRemaps()
+	}
+
+	if b.templateValues {
+		if err := b.renderTemplates(); err != nil {
+			log.Printf("error rendering config templates: %v\n", err)
+			return false
+		}
+	}
+
+	if len(b.keyTypes) > 0 {
+		if err := b.applyKeyTypes(); err != nil {
+			log.Printf("error coercing config values: %v\n", err)
+			return false
+		}
+	}
+
+	if len(b.pathKeys) > 0 {
+		if err := b.resolvePathKeys(); err != nil {
+			log.Printf("error resolving config paths: %v\n", err)
+			return false
+		}
+	}
+
+	if err := b.validate(); err != nil {
+		log.Printf("error validating config, last good config is kept: %v\n", err)
+		return false
+	}
+
+	return true
+}
+
+// debounce wraps handler, so it is called with the last event once no other event
+// was received during the d window. The timer goroutine stops when ctx is done, the returned
+// channel is closed once it exits, so a handler call in progress is finished.
+func debounce(
+	ctx context.Context,
+	d time.Duration,
+	handler func(in fsnotify.Event),
+) (func(in fsnotify.Event), <-chan struct{}) {
+	var (
+		events = make(chan fsnotify.Event)
+		done   = make(chan struct{})
+	)
+
+	go func() {
+		defer close(done)
+
+		var (
+			timer = time.NewTimer(d)
+			fire  <-chan time.Time
+			last  fsnotify.Event
+		)
+
+		timer.Stop()
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case last = <-events:
+				if !timer.Stop() {
+					select {
+					case <-timer.C:
+					default:
+					}
+				}
+
+				timer.Reset(d)
+				fire = timer.C
+			case <-fire:
+				fire = nil
+				handler(last)
+			}
+		}
+	}()
+
+	return func(in fsnotify.Event) {
+		select {
+		case events <- in:
+		case <-ctx.Done():
+		}
+	}, done
+}
+
+// runOnLoad calls load hooks and aggregates their errors.
+func (b *Bundle) runOnLoad() error {
+	var messages []string
+	for _, fn := range b.onLoad {
+		if err := fn(b.viper); err != nil {
+			messages = append(messages, err.Error())
+		}
+	}
+
+	if len(messages) > 0 {
+		return fmt.Errorf("load hooks failed : %s", strings.Join(messages, "; "))
+	}
+
+	return nil
+}
+
+// restore restores previously loaded config after failed reload.
+func (b *Bundle) restore(config map[string]interface{}, fileConfig map[string]interface{}) {
+	b.config, b.fileConfig = config, fileConfig
+	if err := b.replaceConfig(config); err != nil {
+		log.Printf("error restoring config: %v\n", err)
+		return
+	}
+
+	b.applyFilePreferred()
+
+	if len(b.whenDefaults) > 0 {
+		b.applyConditionalDefaults()
+	}
+}
+
+// watchPaths watches all config search paths and dispatches events of config file candidates to handler.
+// Search paths which don't exist are skipped.
+func (b *Bundle) watchPaths(ctx context.Context, handler func(in fsnotify.Event)) (<-chan struct{}, error) {
+	var watcher, err = fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create file watcher : %w", err)
+	}
+
+	for _, dir := range b.searchPaths() {
+		if err = watcher.Add(dir); err != nil && !errors.Is(err, os.ErrNotExist) {
+			_ = watcher.Close()
+			return nil, fmt.Errorf("unable to watch config path : '%s' : %w", dir, err)
+		}
+	}
+
+	var names = b.configNames
+	if len(names) == 0 {
+		names = []string{b.configName}
+	}
+
+	var exts = configExts()
+
+	var candidates = make(map[string]bool, len(names)*len(exts))
+	for _, name := range names {
+		for _, ext := range exts {
+			candidates[name+"."+ext] = true
+		}
+	}
+
+	return runWatcher(ctx, watcher, func(event fsnotify.Event) bool {
+		return candidates[filepath.Base(event.Name)]
+	}, handler), nil
+}
+
+// reselectConfigFile switches to the highest priority config file found in search paths and re-reads it,
+// it reports whether the config should be reloaded on the event.
+func (b *Bundle) reselectConfigFile(in fsnotify.Event) bool {
+	var found = b.findConfigFile()
+	if len(found) == 0 {
+		return false
+	}
+
+	var (
+		current, _ = filepath.Abs(b.viper.ConfigFileUsed())
+		changed, _ = filepath.Abs(in.Name)
+	)
+
+	if found, _ = filepath.Abs(found); found == current && changed != found {
+		return false
+	}
+
+	b.viper.SetConfigFile(found)
+	if err := b.readInConfig(); err != nil {
+		log.Printf("error reading config file: %v\n", err)
+		return false
+	}
+
+	return true
+}
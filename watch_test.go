@@ -0,0 +1,420 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package viper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebounce(t *testing.T) {
+	var (
+		calls       int32
+		last        atomic.Value
+		ctx, cancel = context.WithCancel(context.Background())
+	)
+
+	defer cancel()
+
+	var handler, _ = debounce(ctx, 50*time.Millisecond, func(in fsnotify.Event) {
+		atomic.AddInt32(&calls, 1)
+		last.Store(in.Name)
+	})
+
+	for _, name := range []string{"first", "second", "third"} {
+		handler(fsnotify.Event{Name: name, Op: fsnotify.Write})
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&calls) > 0
+	}, time.Second, 10*time.Millisecond)
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	assert.Equal(t, "third", last.Load())
+}
+
+func TestDebounce_Cancel(t *testing.T) {
+	var (
+		calls            int32
+		ctx, cancel      = context.WithCancel(context.Background())
+		handler, stopped = debounce(ctx, 50*time.Millisecond, func(in fsnotify.Event) {
+			atomic.AddInt32(&calls, 1)
+		})
+	)
+
+	handler(fsnotify.Event{Name: "config.json", Op: fsnotify.Write})
+	cancel()
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("debounce goroutine must exit on cancel")
+	}
+
+	var done = make(chan struct{})
+	go func() {
+		defer close(done)
+		handler(fsnotify.Event{Name: "config.json", Op: fsnotify.Write})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("handler is blocked after cancel")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls))
+}
+
+func TestBundle_WatchDebounce(t *testing.T) {
+	var (
+		dir   = configDir(t, map[string]string{"config.json": `{"a":1}`})
+		calls int32
+		b     = NewBundle(WatchDebounce(100*time.Millisecond), OnChange(func(in fsnotify.Event) {
+			atomic.AddInt32(&calls, 1)
+		}))
+	)
+
+	var v, err = provide(t, b, dir)
+	require.NoError(t, err)
+
+	for i := 2; i <= 4; i++ {
+		writeFile(t, v.ConfigFileUsed(), fmt.Sprintf(`{"a":%d}`, i))
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&calls) > 0
+	}, 2*time.Second, 10*time.Millisecond)
+
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	assert.Equal(t, 4, v.GetInt("a"))
+}
+
+func TestBundle_WatchFile(t *testing.T) {
+	var (
+		dir   = configDir(t, map[string]string{"flags.json": `{"feature": false}`, "other.json": `{}`})
+		file  = filepath.Join(dir, "flags.json")
+		calls int32
+		b     = NewBundle(WatchDebounce(20 * time.Millisecond))
+	)
+
+	var stop, err = b.WatchFile(file, func() {
+		atomic.AddInt32(&calls, 1)
+	})
+
+	require.NoError(t, err)
+
+	writeFile(t, filepath.Join(dir, "other.json"), `{"changed": true}`)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls), "other file change must be ignored")
+
+	writeFile(t, file, `{"feature": true}`)
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&calls) == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	replaceFile(t, file, `{"feature": false}`)
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&calls) == 2
+	}, 2*time.Second, 10*time.Millisecond, "file replaced by rename must be detected")
+
+	stop()
+
+	writeFile(t, file, `{"feature": true}`)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls), "stopped watcher must not call callback")
+}
+
+func TestBundle_WatchFileStopWaitsCallback(t *testing.T) {
+	var (
+		file     = filepath.Join(configDir(t, map[string]string{"flags.json": `{}`}), "flags.json")
+		started  = make(chan struct{})
+		once     sync.Once
+		finished int32
+		b        = NewBundle(WatchDebounce(10 * time.Millisecond))
+	)
+
+	var stop, err = b.WatchFile(file, func() {
+		once.Do(func() { close(started) })
+		time.Sleep(100 * time.Millisecond)
+		atomic.StoreInt32(&finished, 1)
+	})
+
+	require.NoError(t, err)
+
+	writeFile(t, file, `{"feature": true}`)
+
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		require.Fail(t, "callback must be called")
+	}
+
+	stop()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&finished), "stop must wait for the callback in progress")
+}
+
+func TestBundle_WatchFileMissingDir(t *testing.T) {
+	var _, err = NewBundle().WatchFile(filepath.Join(t.TempDir(), "missing", "flags.json"), func() {})
+	assert.Error(t, err)
+}
+
+func TestBundle_OnLoad(t *testing.T) {
+	var dir = configDir(t, map[string]string{
+		"config.json": `{"pool": {"size": 1}}`,
+	})
+
+	var (
+		calls int32
+		sizes = make(chan int, 10)
+		b     = NewBundle(OnLoad(func(v *viper.Viper) error {
+			atomic.AddInt32(&calls, 1)
+			sizes <- v.GetInt("pool.size")
+
+			return nil
+		}))
+	)
+
+	var v, err = provide(t, b, dir)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	require.Equal(t, 1, <-sizes)
+
+	replaceFile(t, v.ConfigFileUsed(), `{"pool": {"size": 2}}`)
+
+	select {
+	case size := <-sizes:
+		require.Equal(t, 2, size, "hook must see reloaded config")
+	case <-time.After(2 * time.Second):
+		require.Fail(t, "hook must run after reload")
+	}
+}
+
+func TestBundle_OnLoadError(t *testing.T) {
+	var dir = configDir(t, map[string]string{
+		"config.json": `{"pool": {"size": 1}}`,
+	})
+
+	var _, err = provide(t, NewBundle(
+		OnLoad(func(*viper.Viper) error { return errors.New("first") }),
+		OnLoad(func(*viper.Viper) error { return nil }),
+		OnLoad(func(*viper.Viper) error { return errors.New("second") }),
+	), dir)
+
+	require.ErrorContains(t, err, "first; second")
+
+	var (
+		failed int32
+		b      = NewBundle(OnLoad(func(v *viper.Viper) error {
+			if v.GetInt("pool.size") < 0 {
+				atomic.AddInt32(&failed, 1)
+				return errors.New("invalid pool size")
+			}
+
+			return nil
+		}))
+	)
+
+	v, err := provide(t, b, dir)
+	require.NoError(t, err)
+
+	replaceFile(t, v.ConfigFileUsed(), `{"pool": {"size": -1}}`)
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&failed) == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	require.Eventually(t, func() bool {
+		return b.View().GetInt("pool.size") == 1
+	}, 2*time.Second, 10*time.Millisecond, "failed reload must keep last good config")
+}
+
+func TestBundle_ConditionalDefaultReload(t *testing.T) {
+	var isProd = func(v *viper.Viper) bool {
+		return v.GetString("mode") == "prod"
+	}
+
+	var (
+		dir = configDir(t, map[string]string{"config.json": `{"mode": "prod"}`})
+		b   = NewBundle(
+			Default("log.level", "debug"),
+			ConditionalDefault("log.level", isProd, "info"),
+			ConditionalDefault("log.format", isProd, "json"),
+			OnChange(func(fsnotify.Event) {}),
+		)
+	)
+
+	var v, err = provide(t, b, dir)
+	require.NoError(t, err)
+	require.Equal(t, "info", b.View().GetString("log.level"))
+	require.Equal(t, "json", b.View().GetString("log.format"))
+
+	replaceFile(t, v.ConfigFileUsed(), `{"mode": "dev"}`)
+	require.Eventually(t, func() bool {
+		return b.View().GetString("log.level") == "debug"
+	}, 2*time.Second, 10*time.Millisecond, "conditional default must be reverted to static default")
+	require.False(t, b.View().IsSet("log.format"), "conditional default without static default must be unset")
+
+	replaceFile(t, v.ConfigFileUsed(), `{"mode": "prod"}`)
+	require.Eventually(t, func() bool {
+		return b.View().GetString("log.level") == "info" && b.View().GetString("log.format") == "json"
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestBundle_ReloadInvalid(t *testing.T) {
+	var (
+		dir = configDir(t, map[string]string{"config.json": `{"mode": "dev", "db": {"host": "localhost"}}`})
+		b   = NewBundle(RequireEnum("mode", "dev", "prod"), Required("db.host"), OnChange(func(fsnotify.Event) {}))
+	)
+
+	var v, err = provide(t, b, dir)
+	require.NoError(t, err)
+
+	for _, content := range []string{`{"mode": "bogus", "db": {"host": "localhost"}}`, `{"mode": "prod"}`} {
+		replaceFile(t, v.ConfigFileUsed(), content)
+		require.Never(t, func() bool {
+			return b.View().GetString("mode") != "dev"
+		}, 500*time.Millisecond, 10*time.Millisecond, "invalid reload must keep last good config")
+	}
+
+	replaceFile(t, v.ConfigFileUsed(), `{"mode": "prod", "db": {"host": "remote"}}`)
+	require.Eventually(t, func() bool {
+		return b.View().GetString("mode") == "prod" && b.View().GetString("db.host") == "remote"
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestBundle_WatchAllPaths(t *testing.T) {
+	var (
+		high = t.TempDir()
+		low  = configDir(t, map[string]string{"config.json": `{"name": "low"}`})
+		b    = NewBundle(ConfigPath(high), WatchAllPaths(), OnChange(func(fsnotify.Event) {}))
+	)
+
+	var v, err = provide(t, b, low)
+	require.NoError(t, err)
+	require.Equal(t, "low", v.GetString("name"))
+
+	writeFile(t, filepath.Join(low, "other.json"), `{"name": "other"}`)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, "low", b.View().GetString("name"), "unrelated file must be ignored")
+
+	replaceFile(t, filepath.Join(low, "config.json"), `{"name": "low reloaded"}`)
+	require.Eventually(t, func() bool {
+		return b.View().GetString("name") == "low reloaded"
+	}, 2*time.Second, 10*time.Millisecond, "current config file change must be reloaded")
+
+	replaceFile(t, filepath.Join(high, "config.json"), `{"name": "high"}`)
+	require.Eventually(t, func() bool {
+		return b.View().GetString("name") == "high"
+	}, 2*time.Second, 10*time.Millisecond, "higher priority config file must take over")
+
+	assert.Equal(t, filepath.Join(high, "config.json"), b.View().ConfigFileUsed())
+
+	replaceFile(t, filepath.Join(low, "config.json"), `{"name": "low changed"}`)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, "high", b.View().GetString("name"), "lower priority config file must be ignored")
+}
+
+func TestBundle_WatchContextCancel(t *testing.T) {
+	setArgs(t)
+
+	var (
+		dir         = configDir(t, map[string]string{"config.json": `{"name": "app"}`})
+		calls       int32
+		b           = NewBundle(OnChange(func(fsnotify.Event) { atomic.AddInt32(&calls, 1) }))
+		ctx, cancel = context.WithCancel(context.WithValue(context.Background(), "app.path", dir))
+	)
+
+	defer cancel()
+
+	var flagSet, err = b.provideFlagSet()
+	require.NoError(t, err)
+
+	v, closer, err := b.provideViper(ctx, flagSet, nil, nil)
+	require.NoError(t, err)
+	require.NotNil(t, b.watchDone)
+
+	var done = b.watchDone
+
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		require.Fail(t, "watcher goroutine must exit on context cancel")
+	}
+
+	writeFile(t, v.ConfigFileUsed(), `{"name": "changed"}`)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls), "cancelled watcher must not call handlers")
+	assert.NoError(t, closer())
+}
+
+func TestBundle_WatchContainerClose(t *testing.T) {
+	var (
+		dir = configDir(t, map[string]string{"config.json": `{"name": "app"}`})
+		b   = NewBundle(OnChange(func(fsnotify.Event) {}))
+		ctn = buildContainer(t, dir, b)
+	)
+
+	var v *viper.Viper
+	require.NoError(t, ctn.Resolve(&v))
+	require.NotNil(t, b.watchDone)
+
+	var done = b.watchDone
+	require.NoError(t, ctn.Close())
+
+	select {
+	case <-done:
+	default:
+		require.Fail(t, "watcher goroutine must exit on container close")
+	}
+
+	assert.Nil(t, b.watchDone)
+}
+
+func TestBundle_StopWatchDebouncedReload(t *testing.T) {
+	var (
+		dir      = configDir(t, map[string]string{"config.json": `{"name": "app"}`})
+		started  = make(chan struct{})
+		once     sync.Once
+		finished int32
+		b        = NewBundle(WatchDebounce(10*time.Millisecond), OnChange(func(fsnotify.Event) {
+			once.Do(func() { close(started) })
+			time.Sleep(100 * time.Millisecond)
+			atomic.StoreInt32(&finished, 1)
+		}))
+	)
+
+	var v, err = provide(t, b, dir)
+	require.NoError(t, err)
+
+	replaceFile(t, v.ConfigFileUsed(), `{"name": "changed"}`)
+
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		require.Fail(t, "debounced reload must be started")
+	}
+
+	b.stopWatch()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&finished), "stop must wait for the debounced reload in progress")
+	assert.Nil(t, b.debounceDone)
+}